@@ -1,40 +1,65 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"mac-dictation/internal/audio"
 	"mac-dictation/internal/database"
+	"mac-dictation/internal/journal"
+	"mac-dictation/internal/llm"
 	"mac-dictation/internal/prompts"
 	"mac-dictation/internal/storage"
 	"mac-dictation/internal/transcription"
+	"path/filepath"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 const (
+	EventStateChanged            = "state:changed"
 	EventRecordingStarted        = "recording:started"
 	EventRecordingProgress       = "recording:progress"
 	EventRecordingStopped        = "recording:stopped"
+	EventRecordingSilence        = "recording:silence-warning"
+	EventRecordingRolledOver     = "recording:rolled-over"
+	EventRecordingTooShort       = "recording:too-short"
 	EventTranscriptionProcessing = "transcription:processing"
 	EventTranscriptionInterim    = "transcription:interim"
+	EventTranscriptionRecovered  = "transcription:recovered"
+	EventTranscriptionReconnect  = "transcription:reconnect"
 	EventTranscriptionDone       = "transcription:completed"
 	EventTitleGenerated          = "thread:title-generated"
 	EventTextImproved            = "message:text-improved"
+	EventTextImproving           = "message:text-improving"
+	EventTextUpdated             = "message:text-updated"
 	EventError                   = "error"
+	EventWarning                 = "warning"
 
 	// Used for enabled/disabled tray icon labels
 	LabelEnabled = false
 )
 
 const (
-	// MaxTranscriptionBytes limits recordings automatically transcribed to 7 minutes
-	//
-	// TODO: We should consolidate all the sampling behaviour as we have this across deepgram/audio impls
+	// MaxTranscriptionBytes limits recordings automatically transcribed to 7 minutes.
+	// Longer recordings are rolled over into a new message in the same thread.
 	MaxTranscriptionBytes = 7 * 60 * audio.BytesPerSecond
 )
 
+const (
+	// SendChunkBytes coalesces captured frames into 100ms chunks before sending to the transcriber
+	SendChunkBytes = audio.BytesPerSecond / 10
+	// SendQueueCapacity holds 30 seconds of audio waiting on a slow connection
+	// before the oldest audio is dropped
+	SendQueueCapacity = 30 * audio.BytesPerSecond
+)
+
 const (
 	// TrayClickStopsRecording controls whether clicking the tray icon while
 	// recording will stop the recording. When false, clicking the tray icon
@@ -43,9 +68,46 @@ const (
 )
 
 const (
-	SettingDeepgramAPIKey       = "deepgram_api_key"
-	SettingOpenAIAPIKey         = "openai_api_key"
-	SettingMinRecordingDuration = "min_recording_duration"
+	SettingDeepgramAPIKey           = "deepgram_api_key"
+	SettingTranscriptionProvider    = "transcription_provider"
+	SettingOpenAIAPIKey             = "openai_api_key"
+	SettingOpenAITranscriptionModel = "openai_transcription_model"
+	SettingLocalTranscriptionURL    = "local_transcription_url"
+	SettingLocalTranscriptionModel  = "local_transcription_model"
+	SettingLLMProvider              = "llm_provider"
+	SettingLLMModel                 = "llm_model"
+	SettingLLMBaseURL               = "llm_base_url"
+	SettingLLMAPIKey                = "llm_api_key"
+	SettingAnthropicAPIKey          = "anthropic_api_key"
+	SettingDeepgramLanguage         = "deepgram_language"
+	SettingDeepgramModel            = "deepgram_model"
+	SettingDeepgramSmartFormat      = "deepgram_smart_format"
+	SettingDeepgramNumerals         = "deepgram_numerals"
+	SettingDeepgramProfanityFilter  = "deepgram_profanity_filter"
+	SettingDeepgramEndpointing      = "deepgram_endpointing"
+	SettingDeepgramUtteranceEndMs   = "deepgram_utterance_end_ms"
+	SettingDeepgramDiarize          = "deepgram_diarize"
+	SettingMinRecordingDuration     = "min_recording_duration"
+	SettingKeepShortRecordings      = "keep_short_recordings"
+	SettingInputDeviceID            = "input_device_id"
+	SettingVADEnabled               = "vad_enabled"
+	SettingVADSilenceThreshold      = "vad_silence_threshold"
+	SettingVADSilenceTimeout        = "vad_silence_timeout"
+)
+
+const (
+	// DefaultVADSilenceThreshold is the RMS level below which audio counts as silence
+	DefaultVADSilenceThreshold = 0.01
+	// DefaultVADSilenceTimeout is the trailing silence in seconds before auto-stopping
+	DefaultVADSilenceTimeout = 10
+	// VADWarningSecs is how long before auto-stopping that the silence warning is emitted
+	VADWarningSecs = 3
+)
+
+const (
+	// DraftThreadName is used for threads created to hold draft messages,
+	// these threads do not get a generated title
+	DraftThreadName = "Draft"
 )
 
 type App struct {
@@ -56,64 +118,356 @@ type App struct {
 	menuStopRecording   *application.MenuItem
 	menuCancelRecording *application.MenuItem
 
-	recorder    *audio.Recorder
-	transcriber transcription.Provider
-	openAi      *transcription.OpenAiService
-
-	messages *storage.MessageService
-	threads  *storage.ThreadService
-	settings *storage.SettingsService
+	recorder *audio.Recorder
+	// llm generates thread titles and cleans up transcripts, selected via SettingLLMProvider.
+	// llmMu guards swapping it when the LLM settings change. llmErr holds why
+	// the selected provider could not be built, llm is nil while it is set.
+	llmMu  sync.Mutex
+	llm    llm.LLM
+	llmErr error
+
+	// state owns the recording lifecycle, every start, stop and cancel must transition it
+	state *recordingStateMachine
+
+	// sendQueue carries captured audio to the transcriber off the audio thread
+	sendQueue *audio.SendQueue
+
+	// providers holds the transcription providers selectable via SettingTranscriptionProvider.
+	// transcriberMu guards swapping the active provider. Open sessions keep the
+	// provider that started them, so it can be swapped mid-recording.
+	providers       *transcription.Registry
+	transcriberMu   sync.Mutex
+	transcriber     transcription.Provider
+	transcriberName string
+
+	messages   *storage.MessageService
+	words      *storage.MessageWordService
+	threads    *storage.ThreadService
+	speakers   *storage.ThreadSpeakerService
+	settings   *storage.SettingsService
+	vocabulary *storage.VocabularyService
+
+	templates *storage.PromptTemplateService
+	revisions *storage.MessageRevisionService
+
+	// recordingsDir is where raw recording audio is persisted as WAV files
+	recordingsDir string
+	// journalDir is where in-progress recordings are journaled for crash recovery
+	journalDir string
+
+	// streamMu guards the open stream, which is swapped when a long recording
+	// is rolled over. Cancelling recordingCtx aborts the stream and any batch
+	// transcription of the recording.
+	streamMu        sync.Mutex
+	stream          *activeStream
+	recordingCtx    context.Context
+	cancelRecording context.CancelFunc
 
 	activeThreadID *int
+
+	// improving and transforming hold the cancel func of each in-flight text
+	// improvement and template transformation, by message ID
+	improveMu    sync.Mutex
+	improving    map[int]context.CancelFunc
+	transforming map[int]context.CancelFunc
 }
 
-func NewApp(db *database.DB) *App {
+func NewApp(db *database.DB, dataDir string) *App {
 	settingsService := storage.NewSettingsService(db)
+	vocabularyService := storage.NewVocabularyService(db)
 
-	deepgramApiKey, _ := settingsService.Get(SettingDeepgramAPIKey)
-	openAiApiKey, _ := settingsService.Get(SettingOpenAIAPIKey)
+	a := &App{
+		recorder:  audio.NewRecorder(),
+		providers: newProviderRegistry(settingsService, vocabularyService),
 
-	return &App{
-		recorder:    audio.NewRecorder(),
-		transcriber: transcription.NewDeepgramService(deepgramApiKey),
-		openAi:      transcription.NewOpenAiService(openAiApiKey),
+		messages:   storage.NewMessageService(db),
+		words:      storage.NewMessageWordService(db),
+		threads:    storage.NewThreadService(db),
+		speakers:   storage.NewThreadSpeakerService(db),
+		settings:   settingsService,
+		vocabulary: vocabularyService,
 
-		messages: storage.NewMessageService(db),
-		threads:  storage.NewThreadService(db),
-		settings: settingsService,
+		templates: storage.NewPromptTemplateService(db),
+		revisions: storage.NewMessageRevisionService(db),
+
+		recordingsDir: filepath.Join(dataDir, "recordings"),
+		journalDir:    filepath.Join(dataDir, "journal"),
+
+		improving:    make(map[int]context.CancelFunc),
+		transforming: make(map[int]context.CancelFunc),
+	}
+	a.state = newRecordingStateMachine(a.onStateChanged)
+	a.seedPromptTemplates()
+	a.reloadLLM()
+	a.reloadTranscriber()
+
+	return a
+}
+
+func (a *App) onStateChanged(event StateChangedEvent) {
+	if a.app != nil {
+		a.app.Event.Emit(EventStateChanged, event)
 	}
+	// Called while the state machine is locked, so the state must not be read back
+	a.setMenuState(event.State)
+}
+
+// reloadLLM rebuilds the LLM from settings. Requests in flight finish on the LLM that started them.
+func (a *App) reloadLLM() {
+	model, err := newLLM(a.settings)
+	if err != nil {
+		slog.Error("failed to load llm provider", "error", err)
+	}
+
+	a.llmMu.Lock()
+	a.llm, a.llmErr = model, err
+	a.llmMu.Unlock()
+}
+
+// currentLLM returns the LLM selected in settings, or why it could not be loaded
+func (a *App) currentLLM() (llm.LLM, error) {
+	a.llmMu.Lock()
+	defer a.llmMu.Unlock()
+	return a.llm, a.llmErr
+}
+
+// GetRecordingState returns the current stage of the recording lifecycle
+func (a *App) GetRecordingState() RecordingState {
+	return a.state.Current()
+}
+
+// reloadTranscriber rebuilds the active provider from settings. An open
+// stream is unaffected and finishes on the provider that started it.
+func (a *App) reloadTranscriber() {
+	a.transcriberMu.Lock()
+	defer a.transcriberMu.Unlock()
+
+	name, _ := a.settings.Get(SettingTranscriptionProvider)
+	if name == "" {
+		name = transcription.ProviderDeepgram
+	}
+
+	provider, err := a.providers.New(name)
+	if err != nil {
+		slog.Error("failed to load transcription provider, using default", "error", err, "provider", name)
+		name = transcription.ProviderDeepgram
+		provider, _ = a.providers.New(name)
+	}
+
+	slog.Info("loaded transcription provider", "provider", name)
+	a.transcriber = provider
+	a.transcriberName = name
+}
+
+// activeStream is an open transcription session and the provider that started it.
+// session is nil if the provider failed to open a new session on rollover,
+// leaving the audio to be recovered with a batch transcription on stop.
+type activeStream struct {
+	session  transcription.Session
+	provider transcription.Provider
+	name     string
+	// journal is nil if it could not be created, the recording then continues unjournaled
+	journal *journal.Journal
+}
+
+var errNoSession = errors.New("no transcription session open")
+
+func (s *activeStream) send(chunk []byte) error {
+	if s.session == nil {
+		return nil
+	}
+	return s.session.SendChunk(chunk)
+}
+
+func (s *activeStream) end() (transcription.Result, error) {
+	if s.session == nil {
+		return transcription.Result{}, errNoSession
+	}
+	return s.session.EndStream()
+}
+
+func (s *activeStream) cancel() {
+	if s.session != nil {
+		s.session.Cancel()
+	}
+}
+
+// journalWriter returns the journal for the recorder, avoiding a non-nil interface holding a nil journal
+func (s *activeStream) journalWriter() io.Writer {
+	if s.journal == nil {
+		return nil
+	}
+	return s.journal
+}
+
+// finishJournal removes the journal once the recording is saved or discarded.
+// It is kept on disk when saving failed so the recording can be recovered.
+func (s *activeStream) finishJournal(keep bool) {
+	if s.journal == nil {
+		return
+	}
+
+	if keep {
+		if err := s.journal.Close(); err != nil {
+			slog.Error("failed to close recording journal", "error", err, "journal", s.journal.ID())
+		}
+		return
+	}
+	if err := s.journal.Remove(); err != nil {
+		slog.Error("failed to remove recording journal", "error", err, "journal", s.journal.ID())
+	}
+}
+
+// newJournal starts a journal for a stream. Failing to journal should not
+// stop the recording, so errors are only logged.
+func (a *App) newJournal(provider string) *journal.Journal {
+	j, err := journal.Create(a.journalDir, journal.Meta{
+		StartedAt: time.Now(),
+		ThreadID:  a.activeThreadID,
+		Provider:  provider,
+	})
+	if err != nil {
+		slog.Error("failed to create recording journal", "error", err)
+		return nil
+	}
+	return j
+}
+
+// startStream opens a session on the active provider and forwards its results to the frontend
+func (a *App) startStream(ctx context.Context) (*activeStream, error) {
+	a.transcriberMu.Lock()
+	provider, name := a.transcriber, a.transcriberName
+	a.transcriberMu.Unlock()
+
+	session, err := provider.StartStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	j := a.newJournal(name)
+	go a.forwardStreamEvents(session, j)
+	return &activeStream{session: session, provider: provider, name: name, journal: j}, nil
+}
+
+// forwardStreamEvents emits a session's interim results and reconnect progress
+// until it ends, journaling final results
+func (a *App) forwardStreamEvents(session transcription.Session, j *journal.Journal) {
+	results := session.Results()
+	var reconnects <-chan transcription.ReconnectEvent
+	if reconnector, ok := session.(transcription.Reconnector); ok {
+		reconnects = reconnector.Reconnects()
+	}
+
+	for results != nil || reconnects != nil {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			a.app.Event.Emit(EventTranscriptionInterim, map[string]any{
+				"text":    result.Text,
+				"isFinal": result.IsFinal,
+			})
+
+			if result.IsFinal && j != nil {
+				if err := j.AppendSegment(journal.Segment{Text: result.Text, EndSecs: result.EndSecs}); err != nil {
+					slog.Error("failed to journal transcript segment", "error", err, "journal", j.ID())
+				}
+			}
+		case event, ok := <-reconnects:
+			if !ok {
+				reconnects = nil
+				continue
+			}
+			a.app.Event.Emit(EventTranscriptionReconnect, event)
+		}
+	}
+}
+
+// takeStream detaches the open stream so no more audio is sent to it
+func (a *App) takeStream() *activeStream {
+	a.streamMu.Lock()
+	defer a.streamMu.Unlock()
+
+	stream := a.stream
+	a.stream = nil
+	return stream
 }
 
 // StartRecording starts recording using the preconfigured recorder.
 func (a *App) StartRecording() {
-	a.transcriber.OnResult(func(text string, isFinal bool) {
-		a.app.Event.Emit(EventTranscriptionInterim, map[string]any{
-			"text":    text,
-			"isFinal": isFinal,
-		})
-	})
+	if err := a.state.Transition(StateIdle, StateStarting); err != nil {
+		slog.Warn("ignoring start recording", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 
-	if err := a.transcriber.StartStream(); err != nil {
+	stream, err := a.startStream(ctx)
+	if err != nil {
+		cancel()
+		a.transitionState(StateStarting, StateIdle)
 		a.emitError("Error starting transcriber", err)
 		return
 	}
 
-	a.recorder.SetOnChunk(func(chunk []byte) {
-		if err := a.transcriber.SendChunk(chunk); err != nil {
-			slog.Error("Error sending chunk to transcriber", "error", err)
-		}
-	})
+	a.streamMu.Lock()
+	a.stream = stream
+	a.recordingCtx = ctx
+	a.cancelRecording = cancel
+	a.streamMu.Unlock()
+
+	a.sendQueue = audio.NewSendQueue(SendQueueCapacity, SendChunkBytes, audio.DropOldest, a.sendChunk)
+	a.recorder.SetOnChunk(a.sendQueue.Push)
+	a.recorder.SetJournal(stream.journalWriter())
+
+	a.selectInputDevice()
+	autoStop := a.loadAutoStopConfig()
 
 	if err := a.recorder.StartRecording(); err != nil {
-		_, _ = a.transcriber.EndStream()
+		a.takeStream().finishJournal(false)
+		cancel()
+		a.sendQueue.Close()
+		a.transitionState(StateStarting, StateIdle)
 		a.emitError("Error starting recording", err)
 		return
 	}
 
+	a.transitionState(StateStarting, StateRecording)
 	a.app.Event.Emit(EventRecordingStarted)
 	a.updateTrayState(TrayIconRecording, "REC")
 
-	go a.progressLoop()
+	go a.progressLoop(autoStop, a.sendQueue)
+}
+
+// autoStopConfig controls stopping a recording after sustained trailing silence
+type autoStopConfig struct {
+	enabled        bool
+	silenceTimeout float64
+}
+
+func (a *App) loadAutoStopConfig() autoStopConfig {
+	cfg := autoStopConfig{
+		enabled:        a.settings.GetBool(SettingVADEnabled, false),
+		silenceTimeout: a.settings.GetFloat(SettingVADSilenceTimeout, DefaultVADSilenceTimeout),
+	}
+	a.recorder.SetSilenceThreshold(a.settings.GetFloat(SettingVADSilenceThreshold, DefaultVADSilenceThreshold))
+	return cfg
+}
+
+// selectInputDevice points the recorder at the saved input device, falling
+// back to the system default with a warning when the device is unavailable
+func (a *App) selectInputDevice() {
+	deviceID, err := a.settings.Get(SettingInputDeviceID)
+	if err != nil {
+		slog.Error("failed to get input device setting", "error", err)
+	}
+
+	if err := a.recorder.SetInputDevice(deviceID); err != nil {
+		slog.Warn("failed to select input device, using default", "error", err, "deviceID", deviceID)
+		a.app.Event.Emit(EventWarning, "Selected microphone is unavailable, using the default input device")
+	}
 }
 
 type TranscriptionCompletedEvent struct {
@@ -123,23 +477,102 @@ type TranscriptionCompletedEvent struct {
 	Empty       bool            `json:"empty"`
 }
 
+// TranscriptionRecoveredEvent is emitted when the streamed transcript was lost
+// and the text was instead recovered by batch transcribing the recorded audio
+type TranscriptionRecoveredEvent struct {
+	StreamFailed bool `json:"streamFailed"`
+}
+
+// RecordingTooShortEvent is emitted when a recording is shorter than the
+// min_recording_duration setting, and was either discarded or kept as a draft
+type RecordingTooShortEvent struct {
+	DurationSecs    float64 `json:"durationSecs"`
+	MinDurationSecs float64 `json:"minDurationSecs"`
+	KeptAsDraft     bool    `json:"keptAsDraft"`
+}
+
+// transcript is a finished transcription waiting to be persisted as a message
+type transcript struct {
+	text         string
+	words        []transcription.Word
+	provider     string
+	durationSecs float64
+	audioData    []byte
+	draft        bool
+	// recovered transcripts are saved to threadID, or a new thread when it is
+	// nil, leaving the active thread unchanged
+	recovered bool
+	threadID  *int
+}
+
 // StopRecording stops recording, cleans up provider WS and
 // Will use the current activeThreadID to manage creating/appended to thread
 func (a *App) StopRecording() {
+	if err := a.state.Transition(StateRecording, StateFinalizing); err != nil {
+		slog.Warn("ignoring stop recording", "error", err)
+		return
+	}
+	defer a.transitionState(StateFinalizing, StateIdle)
+
+	// A rollover still running owns the stream and the audio it took, so let
+	// it persist its part before this one
+	a.state.WaitRollover()
+
 	durationSecs := a.recorder.GetStatus().DurationSecs
-	// TODO: use audio data for fallback transcription/backup
-	_, err := a.recorder.StopRecording()
-	if err != nil {
-		a.emitError("Error stopping recording", err)
+	audioData, stopErr := a.recorder.StopRecording()
+
+	// Deliver the audio still queued before closing the stream
+	a.sendQueue.Close()
+	stream := a.takeStream()
+	ctx, cancel := a.recordingContext()
+	defer cancel()
+
+	if stopErr != nil {
+		// Nothing was recorded to transcribe, so close the stream rather than
+		// leave it held open by keepalives
+		stream.cancel()
+		stream.finishJournal(false)
+		a.app.Event.Emit(EventRecordingStopped)
+		a.emitError("Error stopping recording", stopErr)
 		a.updateTrayState(TrayIconDefault, "")
 		return
 	}
 
 	a.app.Event.Emit(EventRecordingStopped)
 
-	text, err := a.transcriber.EndStream()
-	if err != nil {
-		a.emitError("Error ending transcriber", err)
+	keepJournal := false
+	defer func() {
+		stream.finishJournal(keepJournal)
+	}()
+
+	dropped := a.sendQueue.Stats().DroppedBytes
+	if dropped > 0 {
+		slog.Warn("audio was dropped from the send queue, transcript will be recovered from the recording", "droppedBytes", dropped)
+	}
+
+	draft := false
+	minDurationSecs := a.settings.GetFloat(SettingMinRecordingDuration, 0)
+	if durationSecs < minDurationSecs {
+		draft = a.settings.GetBool(SettingKeepShortRecordings, false)
+		slog.Info("recording shorter than minimum duration", "durationSecs", durationSecs, "minDurationSecs", minDurationSecs, "keptAsDraft", draft)
+		a.app.Event.Emit(EventRecordingTooShort, RecordingTooShortEvent{
+			DurationSecs:    durationSecs,
+			MinDurationSecs: minDurationSecs,
+			KeptAsDraft:     draft,
+		})
+
+		if !draft {
+			stream.cancel()
+			a.updateTrayState(TrayIconDefault, "")
+			a.app.Event.Emit(EventTranscriptionDone, TranscriptionCompletedEvent{Empty: true})
+			return
+		}
+	}
+
+	provider := stream.name
+	transcribed, streamErr := stream.end()
+	if streamErr != nil {
+		slog.Warn("Error ending transcriber", "error", streamErr)
 
 		// We no longer return the error here
 		//
@@ -147,9 +580,26 @@ func (a *App) StopRecording() {
 		// should continue persisting recording rather than killing the process
 	}
 
+	// The stream broke, missed audio or gave us nothing, but we still have the
+	// raw audio so we can attempt to recover the transcript via the batch API
+	streamIncomplete := streamErr != nil || dropped > 0
+	if needsRecovery(stream.provider, transcribed.Text, streamErr, dropped > 0) && len(audioData) > 0 {
+		a.app.Event.Emit(EventTranscriptionProcessing)
+		a.updateTrayState(TrayIconTranscribing, "...")
+
+		if recovered := a.recoverTranscript(ctx, stream.provider, audioData, streamIncomplete); recovered.Text != "" {
+			transcribed = recovered
+			provider = batchProviderName(stream.provider, provider)
+		}
+	}
+
+	if transcribed.Text == "" && streamErr != nil {
+		a.emitError("Error ending transcriber", streamErr)
+	}
+
 	// TODO: Not sure exactly how i want to handle this yet
 	// but we just 'reset' state if no text captured at all
-	if text == "" {
+	if transcribed.Text == "" {
 		a.updateTrayState(TrayIconDefault, "")
 		a.app.Event.Emit(EventTranscriptionDone, TranscriptionCompletedEvent{
 			Message:     storage.Message{},
@@ -162,8 +612,16 @@ func (a *App) StopRecording() {
 
 	a.app.Event.Emit(EventTranscriptionProcessing)
 	a.updateTrayState(TrayIconTranscribing, "...")
-	result, err := a.persistTranscription(text, durationSecs)
+	result, err := a.persistTranscription(transcript{
+		text:         transcribed.Text,
+		words:        transcribed.Words,
+		provider:     provider,
+		durationSecs: audioDurationSecs(audioData),
+		audioData:    audioData,
+		draft:        draft,
+	})
 	if err != nil {
+		keepJournal = true
 		a.emitError("Error persisting transcription", err)
 		a.updateTrayState(TrayIconDefault, "")
 		return
@@ -173,37 +631,219 @@ func (a *App) StopRecording() {
 	a.updateTrayState(TrayIconDefault, "")
 }
 
-func (a *App) persistTranscription(text string, durationSecs float64) (*TranscriptionCompletedEvent, error) {
+// recoverTranscript batch transcribes recorded audio when the streamed transcript was lost
+func (a *App) recoverTranscript(ctx context.Context, provider transcription.Provider, audioData []byte, streamFailed bool) transcription.Result {
+	recovered, err := provider.Transcribe(ctx, audioData)
+	if err != nil {
+		slog.Error("batch transcription fallback failed", "error", err)
+		return transcription.Result{}
+	}
+
+	if recovered.Text != "" {
+		a.app.Event.Emit(EventTranscriptionRecovered, TranscriptionRecoveredEvent{
+			StreamFailed: streamFailed,
+		})
+	}
+	return recovered
+}
+
+// sendChunk forwards captured audio to the open stream
+func (a *App) sendChunk(chunk []byte) {
+	a.streamMu.Lock()
+	stream := a.stream
+	a.streamMu.Unlock()
+
+	if stream == nil {
+		return
+	}
+	if err := stream.send(chunk); err != nil {
+		slog.Error("Error sending chunk to transcriber", "error", err)
+	}
+}
+
+// recordingContext returns the context of the current recording, which is
+// cancelled by CancelRecording, and a func to release it once finished with
+func (a *App) recordingContext() (context.Context, context.CancelFunc) {
+	a.streamMu.Lock()
+	defer a.streamMu.Unlock()
+
+	if a.recordingCtx == nil {
+		return context.Background(), func() {}
+	}
+	return a.recordingCtx, a.cancelRecording
+}
+
+// rolloverRecording finalizes the audio captured so far into a message and
+// continues the recording on a fresh stream, appending to the same thread.
+// This keeps long dictations within MaxTranscriptionBytes.
+//
+// The caller must have claimed the rollover with BeginRollover, which is
+// released once the rolled over part is persisted.
+func (a *App) rolloverRecording() {
+	defer a.state.EndRollover()
+
+	a.streamMu.Lock()
+	if a.stream == nil {
+		a.streamMu.Unlock()
+		return
+	}
+	ctx := a.recordingCtx
+	previous := a.stream
+	a.streamMu.Unlock()
+
+	// The new stream is dialled before swapping, so audio keeps flowing to the
+	// previous stream and journal until the new one is ready
+	next, startErr := a.startStream(ctx)
+	if startErr != nil {
+		next = &activeStream{provider: previous.provider, name: previous.name, journal: a.newJournal(previous.name)}
+	}
+
+	// Send the queued audio to the stream it was recorded for. Audio captured
+	// after TakeAudio goes to the new stream and journal, chunks wait in the
+	// send queue while streamMu is held.
+	a.sendQueue.Flush()
+	a.streamMu.Lock()
+	if a.stream != previous {
+		a.streamMu.Unlock()
+		next.cancel()
+		next.finishJournal(false)
+		return
+	}
+	audioData := a.recorder.TakeAudio(next.journalWriter())
+	a.stream = next
+	a.streamMu.Unlock()
+
+	if startErr != nil {
+		// Audio keeps being captured, so it can still be recovered by the batch fallback on stop
+		a.emitError("Error restarting transcriber", startErr)
+	}
+
+	slog.Info("rolling over recording", "bytes", len(audioData))
+
+	transcribed, streamErr := previous.end()
+	if streamErr != nil {
+		slog.Warn("Error ending transcriber on rollover", "error", streamErr)
+	}
+
+	provider := previous.name
+	if needsRecovery(previous.provider, transcribed.Text, streamErr, false) && len(audioData) > 0 {
+		if recovered := a.recoverTranscript(ctx, previous.provider, audioData, streamErr != nil); recovered.Text != "" {
+			transcribed = recovered
+			provider = batchProviderName(previous.provider, provider)
+		}
+	}
+
+	if transcribed.Text == "" {
+		previous.finishJournal(false)
+		return
+	}
+
+	result, err := a.persistTranscription(transcript{
+		text:         transcribed.Text,
+		words:        transcribed.Words,
+		provider:     provider,
+		durationSecs: audioDurationSecs(audioData),
+		audioData:    audioData,
+	})
+	previous.finishJournal(err != nil)
+	if err != nil {
+		a.emitError("Error persisting transcription", err)
+		return
+	}
+
+	a.app.Event.Emit(EventRecordingRolledOver, result)
+}
+
+// needsRecovery reports whether recorded audio should be batch transcribed
+// because the stream failed, missed audio or returned nothing. A batch-only
+// provider's stream is already a batch request for the same audio, so it is
+// only repeated when audio was dropped before reaching the stream.
+func needsRecovery(provider transcription.Provider, text string, streamErr error, dropped bool) bool {
+	if transcription.IsBatchOnly(provider) {
+		return dropped
+	}
+	return streamErr != nil || dropped || text == ""
+}
+
+// batchProviderName labels text recovered through a streaming provider's batch API
+func batchProviderName(provider transcription.Provider, name string) string {
+	if transcription.IsBatchOnly(provider) {
+		return name
+	}
+	return name + "-batch"
+}
+
+func audioDurationSecs(audioData []byte) float64 {
+	return float64(len(audioData)) / audio.BytesPerSecond
+}
+
+func (a *App) persistTranscription(t transcript) (*TranscriptionCompletedEvent, error) {
 	var thread *storage.Thread
 	var err error
 	isNewThread := false
 
-	if a.activeThreadID == nil {
-		thread, err = a.createThreadAsync(text)
+	threadID := a.activeThreadID
+	if t.recovered {
+		threadID = t.threadID
+	}
+
+	if threadID == nil {
+		if t.draft {
+			thread, err = a.createThread(DraftThreadName)
+		} else {
+			thread, err = a.createThreadAsync(t.text)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("error creating thread: %w", err)
 		}
 		isNewThread = true
+		threadID = thread.ID
+		if !t.recovered {
+			a.activeThreadID = threadID
+		}
 	} else {
-		thread, err = a.threads.Lookup(*a.activeThreadID)
+		thread, err = a.threads.Lookup(*threadID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to lookup thread: %w", err)
 		}
 	}
 
+	// Failing to save the audio should not lose the transcript, so we only log here
+	audioPath, err := a.saveRecording(t.audioData)
+	if err != nil {
+		slog.Error("failed to save recording audio", "error", err)
+	}
+
 	message := &storage.Message{
-		ThreadID:     *a.activeThreadID,
-		OriginalText: text,
-		Text:         "",
-		Provider:     "deepgram",
-		DurationSecs: durationSecs,
+		ThreadID:     *threadID,
+		OriginalText: t.text,
+		Text:         t.text,
+		Provider:     t.provider,
+		DurationSecs: t.durationSecs,
+		AudioPath:    audioPath,
+		Draft:        t.draft,
 	}
 	if err := a.messages.Persist(message); err != nil {
 		return nil, fmt.Errorf("failed to persist message: %w", err)
 	}
 
+	if err := a.revisions.Persist(&storage.MessageRevision{
+		MessageID: *message.ID,
+		Text:      t.text,
+		Source:    storage.RevisionTranscript,
+		CreatedAt: message.CreatedAt,
+	}); err != nil {
+		slog.Error("failed to persist transcript revision", "error", err, "messageID", *message.ID)
+	}
+
+	if len(t.words) > 0 {
+		if err := a.words.PersistForMessage(*message.ID, toMessageWords(t.words)); err != nil {
+			slog.Error("failed to persist message words", "error", err, "messageID", *message.ID)
+		}
+	}
+
 	if !isNewThread {
-		if err := a.threads.TouchUpdatedAt(*a.activeThreadID); err != nil {
+		if err := a.threads.TouchUpdatedAt(*threadID); err != nil {
 			slog.Error("failed to touch thread updated_at", "error", err)
 		}
 	}
@@ -215,27 +855,74 @@ func (a *App) persistTranscription(text string, durationSecs float64) (*Transcri
 	}, nil
 }
 
+func toMessageWords(words []transcription.Word) []storage.MessageWord {
+	messageWords := make([]storage.MessageWord, 0, len(words))
+	for _, w := range words {
+		messageWords = append(messageWords, storage.MessageWord{
+			Word:       w.Word,
+			Start:      w.Start,
+			End:        w.End,
+			Confidence: w.Confidence,
+			Speaker:    w.Speaker,
+		})
+	}
+	return messageWords
+}
+
+// saveRecording writes raw PCM audio to the recordings directory as a WAV file
+// and returns its path. No file is written when there is no audio.
+func (a *App) saveRecording(audioData []byte) (string, error) {
+	if len(audioData) == 0 {
+		return "", nil
+	}
+
+	name := fmt.Sprintf("recording_%s.wav", time.Now().UTC().Format("20060102_150405.000"))
+	path := filepath.Join(a.recordingsDir, name)
+	if err := audio.WriteWAV(path, audioData); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 // createThreadAsync creates a thread with "Untitled" name and generates title in background
 func (a *App) createThreadAsync(text string) (*storage.Thread, error) {
-	thread := &storage.Thread{Name: "Untitled Chat"}
-	if err := a.threads.Persist(thread); err != nil {
-		slog.Error("failed to persist thread", "error", err)
+	thread, err := a.createThread("Untitled Chat")
+	if err != nil {
 		return nil, err
 	}
-	a.activeThreadID = thread.ID
 
 	go a.generateTitleAsync(*thread.ID, text)
 
 	return thread, nil
 }
 
+// createThread creates a thread with the given name
+func (a *App) createThread(name string) (*storage.Thread, error) {
+	thread := &storage.Thread{Name: name}
+	if err := a.threads.Persist(thread); err != nil {
+		slog.Error("failed to persist thread", "error", err)
+		return nil, err
+	}
+	return thread, nil
+}
+
 type TitleGeneratedEvent struct {
 	ThreadID int    `json:"threadId"`
 	Title    string `json:"title"`
 }
 
 func (a *App) generateTitleAsync(threadID int, text string) {
-	title, err := a.openAi.Prompt(prompts.TitleGenerationPrompt, text)
+	model, err := a.currentLLM()
+	if err != nil {
+		slog.Error("skipping title generation", "error", err, "threadID", threadID)
+		return
+	}
+	if !model.IsConfigured() {
+		slog.Info("skipping title generation, llm not configured", "threadID", threadID)
+		return
+	}
+
+	title, err := model.Prompt(context.Background(), prompts.TitleGenerationPrompt, text)
 	if err != nil {
 		slog.Error("failed to generate title", "error", err)
 		return
@@ -263,18 +950,50 @@ func (a *App) generateTitleAsync(threadID int, text string) {
 }
 
 // ToggleRecording starts or stops recording based on current state.
+// Presses while a recording is starting or finalizing are ignored.
 func (a *App) ToggleRecording() {
-	if a.isRecording() {
-		a.StopRecording()
-	} else {
+	switch state := a.state.Current(); state {
+	case StateIdle:
 		a.StartRecording()
+	case StateRecording:
+		a.StopRecording()
+	default:
+		slog.Info("ignoring toggle recording", "state", state)
 	}
 }
 
 // CancelRecording cancels recording in progress and emits EventRecordingStopped.
+// While a recording is finalizing it aborts the transcription in flight instead.
 func (a *App) CancelRecording() {
+	if err := a.state.Transition(StateRecording, StateFinalizing); err != nil {
+		if a.state.Current() == StateFinalizing {
+			_, cancel := a.recordingContext()
+			cancel()
+			return
+		}
+		slog.Warn("ignoring cancel recording", "error", err)
+		return
+	}
+	defer a.transitionState(StateFinalizing, StateIdle)
+
+	// Abort a rollover still transcribing, then wait so it no longer uses the stream
+	_, cancelRollover := a.recordingContext()
+	cancelRollover()
+	a.state.WaitRollover()
+
 	_ = a.recorder.CancelRecording()
-	_, _ = a.transcriber.EndStream()
+
+	// Cancelling the recording context aborts the stream and any batch transcription in flight
+	stream := a.takeStream()
+	_, cancel := a.recordingContext()
+	cancel()
+	if stream != nil {
+		stream.cancel()
+		stream.finishJournal(false)
+	}
+	if a.sendQueue != nil {
+		a.sendQueue.Close()
+	}
 	a.app.Event.Emit(EventRecordingStopped)
 	a.updateTrayState(TrayIconDefault, "")
 }
@@ -284,10 +1003,67 @@ func (a *App) GetMessages(threadID int) ([]storage.Message, error) {
 	if err != nil {
 		return nil, err
 	}
-	return a.messages.LookupForThread(threadID)
+
+	messages, err := a.messages.LookupForThread(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.attachSpeakerTurns(threadID, messages); err != nil {
+		slog.Error("failed to attach speaker turns", "error", err, "threadID", threadID)
+	}
+	return messages, nil
+}
+
+// attachSpeakerTurns populates the speaker labelled view of diarized messages
+func (a *App) attachSpeakerTurns(threadID int, messages []storage.Message) error {
+	diarized, err := a.words.LookupDiarizedForThread(threadID)
+	if err != nil || len(diarized) == 0 {
+		return err
+	}
+
+	names, err := a.speakers.LookupForThread(threadID)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		words, ok := diarized[*messages[i].ID]
+		if !ok {
+			continue
+		}
+
+		turns := storage.BuildSpeakerTurns(words, names)
+		if len(turns) == 0 {
+			continue
+		}
+		messages[i].Turns = turns
+		messages[i].SpeakerText = storage.SpeakerTranscript(turns)
+	}
+	return nil
+}
+
+// RenameSpeaker names a diarized speaker for every message in a thread.
+// An empty name restores the default "Speaker N" label
+func (a *App) RenameSpeaker(threadID, speaker int, name string) error {
+	if _, err := a.threads.Lookup(threadID); err != nil {
+		return err
+	}
+	return a.speakers.Rename(threadID, speaker, name)
+}
+
+// GetMessageWords returns the word level timings and confidence of a message,
+// empty if the provider did not return word data
+func (a *App) GetMessageWords(messageID int) ([]storage.MessageWord, error) {
+	if _, err := a.messages.Lookup(messageID); err != nil {
+		return nil, err
+	}
+	return a.words.LookupForMessage(messageID)
 }
 
 func (a *App) DeleteMessage(id int) error {
+	a.CancelImproveMessageText(id)
+	a.CancelTransformMessage(id)
 	return a.messages.Delete(id)
 }
 
@@ -296,24 +1072,81 @@ type TextImprovedEvent struct {
 	ImprovedText string `json:"improvedText"`
 }
 
-// ImproveMessageText improves the original text of a message using OpenAI
+// TextImprovingEvent carries a chunk of improved text as it streams in
+type TextImprovingEvent struct {
+	MessageID int    `json:"messageId"`
+	Delta     string `json:"delta"`
+}
+
+// ImproveMessageText improves the original text of a message using the configured LLM
 //
-// Will only process improvements once, otherwise will return existing
+// The improved text is streamed to the frontend as message:text-improving
+// deltas, and saved as a new revision once complete. Running it again with a
+// different prompt or model keeps the earlier result in the message's history.
 func (a *App) ImproveMessageText(messageID int) error {
 	message, err := a.messages.Lookup(messageID)
 	if err != nil {
 		return fmt.Errorf("message not found: %w", err)
 	}
 
-	if message.Text != "" {
+	model, err := a.currentLLM()
+	if err != nil {
+		return err
+	}
+
+	// Without an LLM the rule-based cleanup is used instead. It runs in the
+	// background like an LLM improvement, so the frontend sees the same events.
+	if !model.IsConfigured() {
+		go func() {
+			if _, err := a.cleanUpMessage(message); err != nil {
+				slog.Error("failed to clean up text", "error", err, "messageID", messageID)
+				a.app.Event.Emit(EventError, "Failed to improve text: "+err.Error())
+			}
+		}()
+		return nil
+	}
+
+	terms, err := a.vocabulary.Terms()
+	if err != nil {
+		slog.Error("failed to load vocabulary for text improvement", "error", err)
+	}
+	prompt := prompts.WithVocabulary(a.cleanUpPrompt(), terms)
+
+	a.improveMu.Lock()
+	if _, ok := a.improving[messageID]; ok {
+		a.improveMu.Unlock()
 		return nil
 	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.improving[messageID] = cancel
+	a.improveMu.Unlock()
 
 	go func() {
-		improvedText, err := a.openAi.Prompt(prompts.CleanUpPrompt, message.OriginalText)
+		defer func() {
+			a.improveMu.Lock()
+			delete(a.improving, messageID)
+			a.improveMu.Unlock()
+			cancel()
+		}()
+
+		improvedText, err := model.PromptStream(ctx, prompt, message.OriginalText, func(delta string) {
+			a.app.Event.Emit(EventTextImproving, TextImprovingEvent{
+				MessageID: messageID,
+				Delta:     delta,
+			})
+		})
 		if err != nil {
-			slog.Error("failed to improve text", "error", err, "messageID", messageID)
-			a.app.Event.Emit(EventError, "Failed to improve text: "+err.Error())
+			// Reset any partially streamed text back to the current revision
+			a.app.Event.Emit(EventTextImproved, TextImprovedEvent{MessageID: messageID, ImprovedText: message.Text})
+			if errors.Is(err, context.Canceled) {
+				slog.Info("text improvement cancelled", "messageID", messageID)
+				return
+			}
+			slog.Error("failed to improve text, falling back to rule-based cleanup", "error", err, "messageID", messageID)
+			a.app.Event.Emit(EventError, "Failed to improve text, used basic cleanup instead: "+err.Error())
+			if _, err := a.cleanUpMessage(message); err != nil {
+				slog.Error("failed to clean up text", "error", err, "messageID", messageID)
+			}
 			return
 		}
 
@@ -321,8 +1154,13 @@ func (a *App) ImproveMessageText(messageID int) error {
 			improvedText = message.OriginalText
 		}
 
-		message.Text = improvedText
-		if err := a.messages.Persist(message); err != nil {
+		err = a.addRevision(message, &storage.MessageRevision{
+			Text:   improvedText,
+			Source: storage.RevisionLLM,
+			Prompt: prompt,
+			Model:  model.Model(),
+		})
+		if err != nil {
 			slog.Error("failed to persist improved text", "error", err, "messageID", messageID)
 			return
 		}
@@ -336,11 +1174,68 @@ func (a *App) ImproveMessageText(messageID int) error {
 	return nil
 }
 
+// CancelImproveMessageText aborts an in-flight text improvement, leaving the message unchanged
+func (a *App) CancelImproveMessageText(messageID int) {
+	a.improveMu.Lock()
+	cancel, ok := a.improving[messageID]
+	a.improveMu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+func (a *App) ListInputDevices() ([]audio.InputDevice, error) {
+	return a.recorder.ListInputDevices()
+}
+
+func (a *App) GetVocabulary() ([]storage.VocabularyTerm, error) {
+	return a.vocabulary.LookupAll()
+}
+
+func (a *App) AddVocabularyTerm(term string) (*storage.VocabularyTerm, error) {
+	vocabularyTerm := &storage.VocabularyTerm{Term: term}
+	if err := a.vocabulary.Persist(vocabularyTerm); err != nil {
+		return nil, err
+	}
+	a.reloadTranscriber()
+
+	if terms, err := a.vocabulary.Terms(); err == nil && len(terms) > transcription.MaxKeyterms {
+		a.app.Event.Emit(EventWarning, fmt.Sprintf(
+			"Only the first %d vocabulary terms are sent to Deepgram, %d are saved", transcription.MaxKeyterms, len(terms)))
+	}
+	return vocabularyTerm, nil
+}
+
+func (a *App) UpdateVocabularyTerm(id int, term string) error {
+	vocabularyTerm, err := a.vocabulary.Lookup(id)
+	if err != nil {
+		return err
+	}
+	vocabularyTerm.Term = term
+	if err := a.vocabulary.Persist(vocabularyTerm); err != nil {
+		return err
+	}
+	a.reloadTranscriber()
+	return nil
+}
+
+func (a *App) DeleteVocabularyTerm(id int) error {
+	if err := a.vocabulary.Delete(id); err != nil {
+		return err
+	}
+	a.reloadTranscriber()
+	return nil
+}
+
 func (a *App) GetThreads() ([]storage.Thread, error) {
 	return a.threads.LookupAll()
 }
 
 func (a *App) DeleteThread(id int) error {
+	if err := a.messages.DeleteForThread(id); err != nil {
+		return fmt.Errorf("failed to delete thread messages: %w", err)
+	}
 	return a.threads.Delete(id)
 }
 
@@ -372,28 +1267,81 @@ func (a *App) GetSetting(key string) (string, error) {
 }
 
 func (a *App) SetSetting(key, value string) error {
+	if slices.Contains(deepgramSettings, key) {
+		if err := validateDeepgramSetting(a.settings, key, value); err != nil {
+			return err
+		}
+	}
+
+	if key == SettingTranscriptionProvider && value != "" && !slices.Contains(a.providers.Names(), value) {
+		return fmt.Errorf("unknown transcription provider %q", value)
+	}
+
+	if key == SettingLLMProvider && value != "" && !slices.Contains(llm.Providers, value) {
+		return fmt.Errorf("unknown LLM provider %q", value)
+	}
+
+	// A model belongs to the provider it was chosen for, so switching provider
+	// goes back to the new provider's default model
+	if key == SettingLLMProvider {
+		previous, _ := a.settings.Get(SettingLLMProvider)
+		if cmp.Or(previous, llm.ProviderOpenAI) != cmp.Or(value, llm.ProviderOpenAI) {
+			if err := a.settings.Delete(SettingLLMModel); err != nil {
+				return fmt.Errorf("failed to reset llm model: %w", err)
+			}
+		}
+	}
+
 	if err := a.settings.Set(key, value); err != nil {
 		return err
 	}
 
 	switch key {
-	case SettingDeepgramAPIKey:
-		a.transcriber = transcription.NewDeepgramService(value)
+	case SettingTranscriptionProvider, SettingDeepgramAPIKey, SettingOpenAITranscriptionModel,
+		SettingLocalTranscriptionURL, SettingLocalTranscriptionModel:
+		a.reloadTranscriber()
 	case SettingOpenAIAPIKey:
-		a.openAi = transcription.NewOpenAiService(value)
+		a.reloadLLM()
+		a.reloadTranscriber()
+	case SettingLLMProvider, SettingLLMModel, SettingLLMBaseURL, SettingLLMAPIKey, SettingAnthropicAPIKey:
+		a.reloadLLM()
+	default:
+		if slices.Contains(deepgramSettings, key) {
+			a.reloadTranscriber()
+		}
 	}
 
 	return nil
 }
 
+// GetTranscriptionProviders returns the names of the selectable transcription providers
+func (a *App) GetTranscriptionProviders() []string {
+	return a.providers.Names()
+}
+
+// GetLLMProviders returns the names of the selectable LLM providers
+func (a *App) GetLLMProviders() []string {
+	return llm.Providers
+}
+
 func (a *App) GetAllSettings() (map[string]string, error) {
 	return a.settings.GetAll()
 }
 
+// AreAPIKeysConfigured checks the API key required by the selected transcription provider is set.
+//
+// The LLM used for titles and text improvement is optional, so a local
+// provider can be used entirely offline
 func (a *App) AreAPIKeysConfigured() bool {
-	deepgramKey, _ := a.settings.Get(SettingDeepgramAPIKey)
-	openaiKey, _ := a.settings.Get(SettingOpenAIAPIKey)
-	return deepgramKey != "" && openaiKey != ""
+	a.transcriberMu.Lock()
+	provider := a.transcriberName
+	a.transcriberMu.Unlock()
+
+	if keySetting, ok := providerAPIKeySettings[provider]; ok {
+		key, _ := a.settings.Get(keySetting)
+		return key != ""
+	}
+	return true
 }
 
 func (a *App) ShowSettings() {
@@ -412,32 +1360,83 @@ func (a *App) updateTrayState(icon TrayIcon, label string) {
 }
 
 func (a *App) updateMenuState() {
-	recording := a.isRecording()
+	a.setMenuState(a.state.Current())
+}
+
+func (a *App) setMenuState(state RecordingState) {
 	if a.menuStartRecording != nil {
-		a.menuStartRecording.SetEnabled(!recording)
+		a.menuStartRecording.SetEnabled(state == StateIdle)
 	}
 	if a.menuStopRecording != nil {
-		a.menuStopRecording.SetEnabled(recording)
+		a.menuStopRecording.SetEnabled(state == StateRecording)
 	}
 	if a.menuCancelRecording != nil {
-		a.menuCancelRecording.SetEnabled(recording)
+		a.menuCancelRecording.SetEnabled(state == StateRecording || state == StateFinalizing)
 	}
 }
 
 func (a *App) isRecording() bool {
-	return a.recorder.GetStatus().IsRecording
+	return a.state.Current() == StateRecording
+}
+
+// transitionState applies a transition that the caller already owns, so failure indicates a bug
+func (a *App) transitionState(from, to RecordingState) {
+	if err := a.state.Transition(from, to); err != nil {
+		slog.Error("unexpected recording state", "error", err)
+	}
 }
 
-func (a *App) progressLoop() {
+type RecordingProgressEvent struct {
+	DurationSecs float64 `json:"durationSecs"`
+	Level        float64 `json:"level"`
+	Peak         float64 `json:"peak"`
+	// QueueDepthBytes and DroppedBytes report audio waiting to be sent to the
+	// transcriber and audio lost because the send queue overflowed
+	QueueDepthBytes int   `json:"queueDepthBytes"`
+	DroppedBytes    int64 `json:"droppedBytes"`
+}
+
+type SilenceWarningEvent struct {
+	SecondsRemaining float64 `json:"secondsRemaining"`
+}
+
+func (a *App) progressLoop(autoStop autoStopConfig, queue *audio.SendQueue) {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
+	warned := false
 	for range ticker.C {
 		status := a.recorder.GetStatus()
 		if !status.IsRecording {
 			return
 		}
-		a.app.Event.Emit(EventRecordingProgress, status.DurationSecs)
+
+		if autoStop.enabled {
+			remaining := autoStop.silenceTimeout - status.SilenceSecs
+			if remaining <= 0 {
+				slog.Info("stopping recording after sustained silence", "silenceSecs", status.SilenceSecs)
+				a.StopRecording()
+				return
+			}
+
+			if remaining <= VADWarningSecs && !warned {
+				a.app.Event.Emit(EventRecordingSilence, SilenceWarningEvent{SecondsRemaining: remaining})
+			}
+			warned = remaining <= VADWarningSecs
+		}
+
+		if status.BufferedBytes >= MaxTranscriptionBytes && a.state.BeginRollover() {
+			go a.rolloverRecording()
+		}
+
+		queueStats := queue.Stats()
+		a.app.Event.Emit(EventRecordingProgress, RecordingProgressEvent{
+			DurationSecs:    status.DurationSecs,
+			Level:           status.Level,
+			Peak:            status.Peak,
+			QueueDepthBytes: queueStats.DepthBytes,
+			DroppedBytes:    queueStats.DroppedBytes,
+		})
 	}
 }
 
@@ -10,46 +10,212 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 import * as application$0 from "../github.com/wailsapp/wails/v3/pkg/application/models.js";
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
+import * as audio$0 from "./internal/audio/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as diff$0 from "./internal/diff/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as journal$0 from "./internal/journal/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
 import * as storage$0 from "./internal/storage/models.js";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+export function AddPromptTemplate(name: string, prompt: string): $CancellablePromise<storage$0.PromptTemplate | null> {
+    return $Call.ByID(1496405340, name, prompt).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function AddVocabularyTerm(term: string): $CancellablePromise<storage$0.VocabularyTerm | null> {
+    return $Call.ByID(3426498850, term).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * AreAPIKeysConfigured checks the API key required by the selected transcription provider is set.
+ * 
+ * The LLM used for titles and text improvement is optional, so a local
+ * provider can be used entirely offline
+ */
 export function AreAPIKeysConfigured(): $CancellablePromise<boolean> {
     return $Call.ByID(3002748279);
 }
 
+/**
+ * CancelImproveMessageText aborts an in-flight text improvement, leaving the message unchanged
+ */
+export function CancelImproveMessageText(messageID: number): $CancellablePromise<void> {
+    return $Call.ByID(1994975695, messageID);
+}
+
 /**
  * CancelRecording cancels recording in progress and emits EventRecordingStopped.
+ * While a recording is finalizing it aborts the transcription in flight instead.
  */
 export function CancelRecording(): $CancellablePromise<void> {
     return $Call.ByID(1993463310);
 }
 
+/**
+ * CancelTransformMessage aborts an in-flight transformation, leaving the message unchanged
+ */
+export function CancelTransformMessage(messageID: number): $CancellablePromise<void> {
+    return $Call.ByID(2523957812, messageID);
+}
+
+/**
+ * CleanMessageText tidies a message with the rule-based cleanup, a fast first
+ * pass which needs no LLM. The result is saved as a new revision.
+ */
+export function CleanMessageText(messageID: number): $CancellablePromise<storage$0.Message | null> {
+    return $Call.ByID(3723696782, messageID).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
 export function DeleteMessage(id: number): $CancellablePromise<void> {
     return $Call.ByID(4055978473, id);
 }
 
+export function DeletePromptTemplate(id: number): $CancellablePromise<void> {
+    return $Call.ByID(941985242, id);
+}
+
 export function DeleteThread(id: number): $CancellablePromise<void> {
     return $Call.ByID(1186337974, id);
 }
 
+export function DeleteVocabularyTerm(id: number): $CancellablePromise<void> {
+    return $Call.ByID(257761256, id);
+}
+
+/**
+ * DetectOrphanedJournals finds journals left behind by recordings that never
+ * finished, e.g. because the app crashed. Called at startup, before any recording.
+ */
+export function DetectOrphanedJournals(): $CancellablePromise<journal$0.Entry[]> {
+    return $Call.ByID(3721635281).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+/**
+ * DiffMessageRevisions returns a word level diff from one revision to another
+ */
+export function DiffMessageRevisions(fromID: number, toID: number): $CancellablePromise<diff$0.Op[]> {
+    return $Call.ByID(2634386093, fromID, toID).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+/**
+ * DiscardJournal deletes an unfinished recording without saving it
+ */
+export function DiscardJournal(id: string): $CancellablePromise<void> {
+    return $Call.ByID(251344628, id);
+}
+
+/**
+ * EditMessageText replaces a message's text with a manual edit, keeping the
+ * previous text in its history
+ */
+export function EditMessageText(messageID: number, text: string): $CancellablePromise<storage$0.Message | null> {
+    return $Call.ByID(2726621719, messageID, text).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
 export function GetAllSettings(): $CancellablePromise<{ [_: string]: string }> {
     return $Call.ByID(1224888095).then(($result: any) => {
-        return $$createType0($result);
+        return $$createType10($result);
+    });
+}
+
+/**
+ * GetLLMProviders returns the names of the selectable LLM providers
+ */
+export function GetLLMProviders(): $CancellablePromise<string[]> {
+    return $Call.ByID(2627867344).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+/**
+ * GetMessageRevisions returns every version of a message's text, oldest first.
+ * The last revision is the current text.
+ */
+export function GetMessageRevisions(messageID: number): $CancellablePromise<storage$0.MessageRevision[]> {
+    return $Call.ByID(2609948348, messageID).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+/**
+ * GetMessageWords returns the word level timings and confidence of a message,
+ * empty if the provider did not return word data
+ */
+export function GetMessageWords(messageID: number): $CancellablePromise<storage$0.MessageWord[]> {
+    return $Call.ByID(3842500635, messageID).then(($result: any) => {
+        return $$createType15($result);
     });
 }
 
 export function GetMessages(threadID: number): $CancellablePromise<storage$0.Message[]> {
     return $Call.ByID(3832618599, threadID).then(($result: any) => {
-        return $$createType2($result);
+        return $$createType16($result);
+    });
+}
+
+/**
+ * GetOrphanedJournals returns recordings that can be recovered, excluding the one in progress
+ */
+export function GetOrphanedJournals(): $CancellablePromise<journal$0.Entry[]> {
+    return $Call.ByID(2177106444).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+export function GetPromptTemplates(): $CancellablePromise<storage$0.PromptTemplate[]> {
+    return $Call.ByID(2808121832).then(($result: any) => {
+        return $$createType17($result);
     });
 }
 
+/**
+ * GetRecordingState returns the current stage of the recording lifecycle
+ */
+export function GetRecordingState(): $CancellablePromise<$models.RecordingState> {
+    return $Call.ByID(1629612863);
+}
+
 export function GetSetting(key: string): $CancellablePromise<string> {
     return $Call.ByID(48053349, key);
 }
 
 export function GetThreads(): $CancellablePromise<storage$0.Thread[]> {
     return $Call.ByID(972270404).then(($result: any) => {
-        return $$createType4($result);
+        return $$createType19($result);
+    });
+}
+
+/**
+ * GetTranscriptionProviders returns the names of the selectable transcription providers
+ */
+export function GetTranscriptionProviders(): $CancellablePromise<string[]> {
+    return $Call.ByID(3374564793).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+export function GetVocabulary(): $CancellablePromise<storage$0.VocabularyTerm[]> {
+    return $Call.ByID(2508323303).then(($result: any) => {
+        return $$createType20($result);
     });
 }
 
@@ -58,22 +224,64 @@ export function HideWindow(): $CancellablePromise<void> {
 }
 
 /**
- * ImproveMessageText improves the original text of a message using OpenAI
+ * ImproveMessageText improves the original text of a message using the configured LLM
  * 
- * Will only process improvements once, otherwise will return existing
+ * The improved text is streamed to the frontend as message:text-improving
+ * deltas, and saved as a new revision once complete. Running it again with a
+ * different prompt or model keeps the earlier result in the message's history.
  */
 export function ImproveMessageText(messageID: number): $CancellablePromise<void> {
     return $Call.ByID(4050467057, messageID);
 }
 
+export function ListInputDevices(): $CancellablePromise<audio$0.InputDevice[]> {
+    return $Call.ByID(2210904258).then(($result: any) => {
+        return $$createType22($result);
+    });
+}
+
 export function OnTrayClick(): $CancellablePromise<void> {
     return $Call.ByID(852014744);
 }
 
+/**
+ * RecoverJournal saves an unfinished recording as a message. Final transcript
+ * segments journaled during the recording are kept, and any audio after the
+ * last segment is batch transcribed with the active provider.
+ * 
+ * The message is added to the thread the recording was made in, or a new
+ * thread, without changing the selected thread. Recovery holds the recording
+ * state in finalizing so no recording can start meanwhile, and can be
+ * aborted with CancelRecording.
+ */
+export function RecoverJournal(id: string): $CancellablePromise<$models.TranscriptionCompletedEvent | null> {
+    return $Call.ByID(2065010882, id).then(($result: any) => {
+        return $$createType24($result);
+    });
+}
+
+/**
+ * RenameSpeaker names a diarized speaker for every message in a thread.
+ * An empty name restores the default "Speaker N" label
+ */
+export function RenameSpeaker(threadID: number, speaker: number, name: string): $CancellablePromise<void> {
+    return $Call.ByID(2247269560, threadID, speaker, name);
+}
+
 export function RenameThread(id: number, name: string): $CancellablePromise<void> {
     return $Call.ByID(727416435, id, name);
 }
 
+/**
+ * RestoreMessageRevision makes an earlier revision the message's current text.
+ * The restore is recorded as a new revision so no history is lost.
+ */
+export function RestoreMessageRevision(revisionID: number): $CancellablePromise<storage$0.Message | null> {
+    return $Call.ByID(136056229, revisionID).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
 /**
  * SelectThread sets the active thread. Setting 0 will clear the current thread
  */
@@ -128,9 +336,59 @@ export function StopRecording(): $CancellablePromise<void> {
     return $Call.ByID(3372080196);
 }
 
+/**
+ * ToggleRecording starts or stops recording based on current state.
+ * Presses while a recording is starting or finalizing are ignored.
+ */
+export function ToggleRecording(): $CancellablePromise<void> {
+    return $Call.ByID(1227481556);
+}
+
+/**
+ * TransformMessage runs a prompt template against a message's transcript with
+ * the configured LLM. The result is saved as a revision alongside the message's
+ * history without replacing its current text, so each template run is kept as
+ * its own version that can be compared or restored. It returns nil without an
+ * error when the transformation is cancelled.
+ */
+export function TransformMessage(messageID: number, templateID: number): $CancellablePromise<storage$0.MessageRevision | null> {
+    return $Call.ByID(4286656138, messageID, templateID).then(($result: any) => {
+        return $$createType25($result);
+    });
+}
+
+export function UpdatePromptTemplate(id: number, name: string, prompt: string): $CancellablePromise<void> {
+    return $Call.ByID(1195743628, id, name, prompt);
+}
+
+export function UpdateVocabularyTerm(id: number, term: string): $CancellablePromise<void> {
+    return $Call.ByID(4209075506, id, term);
+}
+
 // Private type creation functions
-const $$createType0 = $Create.Map($Create.Any, $Create.Any);
-const $$createType1 = storage$0.Message.createFrom;
-const $$createType2 = $Create.Array($$createType1);
-const $$createType3 = storage$0.Thread.createFrom;
-const $$createType4 = $Create.Array($$createType3);
+const $$createType0 = storage$0.PromptTemplate.createFrom;
+const $$createType1 = $Create.Nullable($$createType0);
+const $$createType2 = storage$0.VocabularyTerm.createFrom;
+const $$createType3 = $Create.Nullable($$createType2);
+const $$createType4 = storage$0.Message.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
+const $$createType6 = journal$0.Entry.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = diff$0.Op.createFrom;
+const $$createType9 = $Create.Array($$createType8);
+const $$createType10 = $Create.Map($Create.Any, $Create.Any);
+const $$createType11 = $Create.Array($Create.Any);
+const $$createType12 = storage$0.MessageRevision.createFrom;
+const $$createType13 = $Create.Array($$createType12);
+const $$createType14 = storage$0.MessageWord.createFrom;
+const $$createType15 = $Create.Array($$createType14);
+const $$createType16 = $Create.Array($$createType4);
+const $$createType17 = $Create.Array($$createType0);
+const $$createType18 = storage$0.Thread.createFrom;
+const $$createType19 = $Create.Array($$createType18);
+const $$createType20 = $Create.Array($$createType2);
+const $$createType21 = audio$0.InputDevice.createFrom;
+const $$createType22 = $Create.Array($$createType21);
+const $$createType23 = $models.TranscriptionCompletedEvent.createFrom;
+const $$createType24 = $Create.Nullable($$createType23);
+const $$createType25 = $Create.Nullable($$createType12);
@@ -5,3 +5,8 @@ import * as App from "./app.js";
 export {
     App
 };
+
+export {
+    RecordingState,
+    TranscriptionCompletedEvent
+} from "./models.js";
@@ -0,0 +1,38 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * InputDevice describes a capture device available to the recorder
+ */
+export class InputDevice {
+    "id": string;
+    "name": string;
+    "isDefault": boolean;
+
+    /** Creates a new InputDevice instance. */
+    constructor($$source: Partial<InputDevice> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("isDefault" in $$source)) {
+            this["isDefault"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new InputDevice instance from a string or object.
+     */
+    static createFrom($$source: any = {}): InputDevice {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new InputDevice($$parsedSource as Partial<InputDevice>);
+    }
+}
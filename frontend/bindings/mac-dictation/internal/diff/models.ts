@@ -0,0 +1,48 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Op is a run of words that are unchanged, inserted or deleted
+ */
+export class Op {
+    "type": OpType;
+    "text": string;
+
+    /** Creates a new Op instance. */
+    constructor($$source: Partial<Op> = {}) {
+        if (!("type" in $$source)) {
+            this["type"] = OpType.$zero;
+        }
+        if (!("text" in $$source)) {
+            this["text"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Op instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Op {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Op($$parsedSource as Partial<Op>);
+    }
+}
+
+/**
+ * OpType is the kind of change an Op makes
+ */
+export enum OpType {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    Equal = "equal",
+    Insert = "insert",
+    Delete = "delete",
+};
@@ -0,0 +1,95 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../../time/models.js";
+
+/**
+ * Entry is a journal left on disk, typically by a recording that never finished
+ */
+export class Entry {
+    "id": string;
+    "startedAt": time$0.Time;
+
+    /**
+     * ThreadID is the thread the recording would have been added to, nil for a new thread
+     */
+    "threadId": number | null;
+    "provider": string;
+    "durationSecs": number;
+    "segments": Segment[];
+
+    /** Creates a new Entry instance. */
+    constructor($$source: Partial<Entry> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("startedAt" in $$source)) {
+            this["startedAt"] = null;
+        }
+        if (!("threadId" in $$source)) {
+            this["threadId"] = null;
+        }
+        if (!("provider" in $$source)) {
+            this["provider"] = "";
+        }
+        if (!("durationSecs" in $$source)) {
+            this["durationSecs"] = 0;
+        }
+        if (!("segments" in $$source)) {
+            this["segments"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Entry instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Entry {
+        const $$createField5_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("segments" in $$parsedSource) {
+            $$parsedSource["segments"] = $$createField5_0($$parsedSource["segments"]);
+        }
+        return new Entry($$parsedSource as Partial<Entry>);
+    }
+}
+
+/**
+ * Segment is a final transcript received while recording. EndSecs is how far
+ * into the journal's audio the text covers.
+ */
+export class Segment {
+    "text": string;
+    "endSecs": number;
+
+    /** Creates a new Segment instance. */
+    constructor($$source: Partial<Segment> = {}) {
+        if (!("text" in $$source)) {
+            this["text"] = "";
+        }
+        if (!("endSecs" in $$source)) {
+            this["endSecs"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Segment instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Segment {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Segment($$parsedSource as Partial<Segment>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Segment.createFrom;
+const $$createType1 = $Create.Array($$createType0);
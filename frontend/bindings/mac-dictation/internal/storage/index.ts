@@ -3,5 +3,11 @@
 
 export {
     Message,
-    Thread
+    MessageRevision,
+    MessageWord,
+    PromptTemplate,
+    RevisionSource,
+    SpeakerTurn,
+    Thread,
+    VocabularyTerm
 } from "./models.js";
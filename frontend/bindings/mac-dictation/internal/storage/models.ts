@@ -16,10 +16,18 @@ export class Message {
     "text": string;
     "provider": string;
     "durationSecs": number;
+    "audioPath": string;
+    "draft": boolean;
     "createdAt": time$0.Time;
     "updatedAt": time$0.Time;
     "deletedAt": time$0.Time | null;
 
+    /**
+     * Turns and SpeakerText are populated for diarized messages, they are not persisted
+     */
+    "turns"?: SpeakerTurn[];
+    "speakerText"?: string;
+
     /** Creates a new Message instance. */
     constructor($$source: Partial<Message> = {}) {
         if (!("id" in $$source)) {
@@ -40,6 +48,12 @@ export class Message {
         if (!("durationSecs" in $$source)) {
             this["durationSecs"] = 0;
         }
+        if (!("audioPath" in $$source)) {
+            this["audioPath"] = "";
+        }
+        if (!("draft" in $$source)) {
+            this["draft"] = false;
+        }
         if (!("createdAt" in $$source)) {
             this["createdAt"] = null;
         }
@@ -57,11 +71,249 @@ export class Message {
      * Creates a new Message instance from a string or object.
      */
     static createFrom($$source: any = {}): Message {
+        const $$createField11_0 = $$createType1;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("turns" in $$parsedSource) {
+            $$parsedSource["turns"] = $$createField11_0($$parsedSource["turns"]);
+        }
         return new Message($$parsedSource as Partial<Message>);
     }
 }
 
+/**
+ * MessageRevision is one version of a message's text. The latest revision that
+ * is not a template run is the message's head, mirrored in Message.Text.
+ */
+export class MessageRevision {
+    "id": number | null;
+    "messageId": number;
+    "text": string;
+    "source": RevisionSource;
+    "prompt": string;
+    "model": string;
+
+    /**
+     * TemplateName is the prompt template a template revision ran, kept for display
+     */
+    "templateName": string;
+
+    /**
+     * RestoredFrom is the revision a restore copied
+     */
+    "restoredFrom": number | null;
+    "createdAt": time$0.Time;
+
+    /** Creates a new MessageRevision instance. */
+    constructor($$source: Partial<MessageRevision> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = null;
+        }
+        if (!("messageId" in $$source)) {
+            this["messageId"] = 0;
+        }
+        if (!("text" in $$source)) {
+            this["text"] = "";
+        }
+        if (!("source" in $$source)) {
+            this["source"] = RevisionSource.$zero;
+        }
+        if (!("prompt" in $$source)) {
+            this["prompt"] = "";
+        }
+        if (!("model" in $$source)) {
+            this["model"] = "";
+        }
+        if (!("templateName" in $$source)) {
+            this["templateName"] = "";
+        }
+        if (!("restoredFrom" in $$source)) {
+            this["restoredFrom"] = null;
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MessageRevision instance from a string or object.
+     */
+    static createFrom($$source: any = {}): MessageRevision {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new MessageRevision($$parsedSource as Partial<MessageRevision>);
+    }
+}
+
+/**
+ * MessageWord is a single transcribed word of a message, with start and end
+ * in seconds from the start of the message audio
+ */
+export class MessageWord {
+    "word": string;
+    "start": number;
+    "end": number;
+    "confidence": number;
+
+    /**
+     * Speaker is set when the transcription was diarized
+     */
+    "speaker"?: number | null;
+
+    /** Creates a new MessageWord instance. */
+    constructor($$source: Partial<MessageWord> = {}) {
+        if (!("word" in $$source)) {
+            this["word"] = "";
+        }
+        if (!("start" in $$source)) {
+            this["start"] = 0;
+        }
+        if (!("end" in $$source)) {
+            this["end"] = 0;
+        }
+        if (!("confidence" in $$source)) {
+            this["confidence"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MessageWord instance from a string or object.
+     */
+    static createFrom($$source: any = {}): MessageWord {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new MessageWord($$parsedSource as Partial<MessageWord>);
+    }
+}
+
+/**
+ * PromptTemplate is a named instruction run against a message's text, such as
+ * "rewrite as an email". Built-in templates are seeded at startup and keep
+ * their BuiltinKey, user created templates have none.
+ */
+export class PromptTemplate {
+    "id": number | null;
+    "builtinKey": string;
+    "name": string;
+    "prompt": string;
+    "createdAt": time$0.Time;
+    "updatedAt": time$0.Time;
+
+    /** Creates a new PromptTemplate instance. */
+    constructor($$source: Partial<PromptTemplate> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = null;
+        }
+        if (!("builtinKey" in $$source)) {
+            this["builtinKey"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("prompt" in $$source)) {
+            this["prompt"] = "";
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = null;
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PromptTemplate instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PromptTemplate {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PromptTemplate($$parsedSource as Partial<PromptTemplate>);
+    }
+}
+
+/**
+ * RevisionSource records what produced a revision of a message's text
+ */
+export enum RevisionSource {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * RevisionTranscript is the text as transcribed
+     */
+    RevisionTranscript = "transcript",
+
+    /**
+     * RevisionLLM is text produced by an LLM, with the prompt and model recorded
+     */
+    RevisionLLM = "llm",
+
+    /**
+     * RevisionTemplate is text produced by running a prompt template with an
+     * LLM. It is kept alongside the history and never becomes the head.
+     */
+    RevisionTemplate = "template",
+
+    /**
+     * RevisionCleanup is text tidied by the rule-based cleanup, without an LLM
+     */
+    RevisionCleanup = "cleanup",
+
+    /**
+     * RevisionManual is text edited by hand
+     */
+    RevisionManual = "manual",
+
+    /**
+     * RevisionRestore is an earlier revision made current again
+     */
+    RevisionRestore = "restore",
+};
+
+/**
+ * SpeakerTurn is a run of consecutive words spoken by the same speaker
+ */
+export class SpeakerTurn {
+    "speaker": number;
+    "label": string;
+    "text": string;
+    "start": number;
+    "end": number;
+
+    /** Creates a new SpeakerTurn instance. */
+    constructor($$source: Partial<SpeakerTurn> = {}) {
+        if (!("speaker" in $$source)) {
+            this["speaker"] = 0;
+        }
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("text" in $$source)) {
+            this["text"] = "";
+        }
+        if (!("start" in $$source)) {
+            this["start"] = 0;
+        }
+        if (!("end" in $$source)) {
+            this["end"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SpeakerTurn instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SpeakerTurn {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SpeakerTurn($$parsedSource as Partial<SpeakerTurn>);
+    }
+}
+
 export class Thread {
     "id": number | null;
     "name": string;
@@ -102,3 +354,44 @@ export class Thread {
         return new Thread($$parsedSource as Partial<Thread>);
     }
 }
+
+/**
+ * VocabularyTerm is a product name, person or piece of jargon that
+ * transcription and cleanup should preserve as written
+ */
+export class VocabularyTerm {
+    "id": number | null;
+    "term": string;
+    "createdAt": time$0.Time;
+    "updatedAt": time$0.Time;
+
+    /** Creates a new VocabularyTerm instance. */
+    constructor($$source: Partial<VocabularyTerm> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = null;
+        }
+        if (!("term" in $$source)) {
+            this["term"] = "";
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = null;
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new VocabularyTerm instance from a string or object.
+     */
+    static createFrom($$source: any = {}): VocabularyTerm {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new VocabularyTerm($$parsedSource as Partial<VocabularyTerm>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = SpeakerTurn.createFrom;
+const $$createType1 = $Create.Array($$createType0);
@@ -0,0 +1,72 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as storage$0 from "./internal/storage/models.js";
+
+/**
+ * RecordingState is a stage in the recording lifecycle:
+ * idle → starting → recording → finalizing → idle
+ */
+export enum RecordingState {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    StateIdle = "idle",
+    StateStarting = "starting",
+    StateRecording = "recording",
+    StateFinalizing = "finalizing",
+};
+
+export class TranscriptionCompletedEvent {
+    "message": storage$0.Message;
+    "thread": storage$0.Thread | null;
+    "isNewThread": boolean;
+    "empty": boolean;
+
+    /** Creates a new TranscriptionCompletedEvent instance. */
+    constructor($$source: Partial<TranscriptionCompletedEvent> = {}) {
+        if (!("message" in $$source)) {
+            this["message"] = (new storage$0.Message());
+        }
+        if (!("thread" in $$source)) {
+            this["thread"] = null;
+        }
+        if (!("isNewThread" in $$source)) {
+            this["isNewThread"] = false;
+        }
+        if (!("empty" in $$source)) {
+            this["empty"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TranscriptionCompletedEvent instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TranscriptionCompletedEvent {
+        const $$createField0_0 = $$createType0;
+        const $$createField1_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("message" in $$parsedSource) {
+            $$parsedSource["message"] = $$createField0_0($$parsedSource["message"]);
+        }
+        if ("thread" in $$parsedSource) {
+            $$parsedSource["thread"] = $$createField1_0($$parsedSource["thread"]);
+        }
+        return new TranscriptionCompletedEvent($$parsedSource as Partial<TranscriptionCompletedEvent>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = storage$0.Message.createFrom;
+const $$createType1 = storage$0.Thread.createFrom;
+const $$createType2 = $Create.Nullable($$createType1);
@@ -11,6 +11,7 @@ import {
     ThreadHeader,
     Settings,
     AlertToast,
+    RecoveryBanner,
 } from './components'
 import { AlertProvider, useAlerts } from './contexts/AlertContext'
 import type { TranscriptionCompletedEvent } from './types'
@@ -26,6 +27,40 @@ function useErrorListener() {
         })
         return () => unsub()
     }, [addAlert])
+
+    useEffect(() => {
+        const unsub = Events.On('warning', (ev: Events.WailsEvent) => {
+            addAlert('warning', ev.data as string)
+        })
+        return () => unsub()
+    }, [addAlert])
+
+    useEffect(() => {
+        const unsub = Events.On('transcription:recovered', (ev: Events.WailsEvent) => {
+            const { streamFailed } = ev.data as { streamFailed: boolean }
+            addAlert(
+                'info',
+                streamFailed
+                    ? 'Live transcription failed, the recording was transcribed in full instead'
+                    : 'Live transcription missed some audio, the recording was transcribed in full instead'
+            )
+        })
+        return () => unsub()
+    }, [addAlert])
+
+    useEffect(() => {
+        const unsub = Events.On('transcription:reconnect', (ev: Events.WailsEvent) => {
+            const { status, attempt } = ev.data as { status: string; attempt: number }
+            if (status === 'reconnecting' && attempt === 1) {
+                addAlert('warning', 'Transcription connection lost, reconnecting...')
+            } else if (status === 'reconnected') {
+                addAlert('success', 'Transcription reconnected')
+            } else if (status === 'failed') {
+                addAlert('error', 'Could not reconnect, the recording will be transcribed when it stops')
+            }
+        })
+        return () => unsub()
+    }, [addAlert])
 }
 
 function AppContent() {
@@ -51,6 +86,21 @@ function AppContent() {
         [threads, messages]
     )
 
+    // A recovered recording is saved without changing the selected thread
+    const handleRecovered = useCallback(
+        (event: TranscriptionCompletedEvent) => {
+            if (event.isNewThread) {
+                threads.refetch()
+            } else if (event.thread) {
+                threads.updateThread(event.thread)
+            }
+            if (event.message.threadId === threads.activeThreadId) {
+                messages.addMessage(event.message)
+            }
+        },
+        [threads, messages]
+    )
+
     const recordingOptions = useMemo(
         () => ({
             onTranscriptionComplete: handleTranscriptionComplete,
@@ -162,12 +212,15 @@ function AppContent() {
                 onCopy={recording.handleCopy}
             />
 
+            <RecoveryBanner onRecovered={handleRecovered} />
+
             <main className="flex-1 min-h-0">
                 <ChatView
                     messages={messages.messages}
                     loading={messages.loading}
                     recordingState={recording.state}
                     durationSecs={recording.durationSecs}
+                    level={recording.level}
                     recordingDisabled={!apiKeysConfigured}
                     interimTranscript={recording.interimTranscript}
                     onStart={recording.startRecording}
@@ -9,6 +9,7 @@ interface Props {
     loading?: boolean
     recordingState: RecordingState
     durationSecs: number
+    level?: number
     recordingDisabled?: boolean
     interimTranscript?: string
     onStart: () => void
@@ -68,6 +69,7 @@ export function ChatView({
     loading = false,
     recordingState,
     durationSecs,
+    level,
     recordingDisabled = false,
     interimTranscript = '',
     onStart,
@@ -211,6 +213,7 @@ export function ChatView({
                         isRecording={isRecording}
                         isProcessing={isProcessing}
                         hasContent={false}
+                        level={level}
                         disabled={recordingDisabled}
                         onStart={onStart}
                         onStop={onStop}
@@ -5,10 +5,17 @@ import {
     LuChevronUp,
     LuCopy,
     LuFileText,
+    LuHistory,
+    LuPencil,
     LuSparkles,
 } from 'react-icons/lu'
+import { Events } from '@wailsio/runtime'
 import { App as AppService } from '../../bindings/mac-dictation'
 import { Message } from '../types'
+import { useAlerts } from '../contexts/AlertContext'
+import { useTransformations } from '../hooks/useTransformations'
+import { TransformMenu } from './MessageTransformations'
+import { RevisionHistory } from './RevisionHistory'
 
 interface Props {
     message: Message
@@ -47,19 +54,46 @@ export function MessageBubble({ message }: Readonly<Props>) {
     const [needsExpansion, setNeedsExpansion] = useState(false)
     const [showImproved, setShowImproved] = useState(true)
     const [isImproving, setIsImproving] = useState(false)
+    const [isEditing, setIsEditing] = useState(false)
+    const [editText, setEditText] = useState('')
+    const [showHistory, setShowHistory] = useState(false)
+    const [transformCount, setTransformCount] = useState(0)
     const textRef = useRef<HTMLDivElement>(null)
+    const handleTransformed = useCallback(() => {
+        setTransformCount((count) => count + 1)
+        setShowHistory(true)
+    }, [])
+    const transformations = useTransformations(
+        message.id ?? null,
+        handleTransformed
+    )
+    const { addAlert } = useAlerts()
 
     const hasImprovedText =
         message.text && message.text !== message.originalText
     const displayText =
         showImproved && hasImprovedText ? message.text : message.originalText
+    // Diarized transcripts are shown turn by turn, labelled with each speaker
+    const speakerView =
+        !!message.turns?.length && !(showImproved && hasImprovedText)
+    const copyText = speakerView
+        ? message.speakerText || displayText
+        : displayText
 
     useEffect(() => {
-        if (hasImprovedText && isImproving) {
-            setIsImproving(false)
-            setShowImproved(true)
-        }
-    }, [hasImprovedText, isImproving])
+        if (!isImproving) return
+        const unsub = Events.On(
+            'message:text-improved',
+            (ev: Events.WailsEvent) => {
+                const data = ev.data as { messageId: number }
+                if (data.messageId === message.id) {
+                    setIsImproving(false)
+                    setShowImproved(true)
+                }
+            }
+        )
+        return () => unsub()
+    }, [isImproving, message.id])
 
     useEffect(() => {
         if (textRef.current) {
@@ -67,36 +101,91 @@ export function MessageBubble({ message }: Readonly<Props>) {
                 textRef.current.scrollHeight > MAX_COLLAPSED_HEIGHT
             )
         }
-    }, [displayText])
+    }, [displayText, speakerView])
 
     const handleCopy = useCallback(async () => {
         try {
-            await navigator.clipboard.writeText(displayText)
+            await navigator.clipboard.writeText(copyText)
             setCopied(true)
             setTimeout(() => setCopied(false), 2000)
         } catch {}
-    }, [displayText])
+    }, [copyText])
 
-    const handleSparkleClick = useCallback(async () => {
+    const improve = useCallback(async () => {
         if (isImproving) return
+        setIsImproving(true)
+        try {
+            await AppService.ImproveMessageText(message.id!)
+        } catch (err) {
+            addAlert('error', `Failed to improve text: ${err}`)
+            setIsImproving(false)
+        }
+    }, [isImproving, message.id, addAlert])
+
+    const handleSparkleClick = useCallback(async () => {
+        if (isImproving) {
+            await AppService.CancelImproveMessageText(message.id!)
+            return
+        }
 
         if (hasImprovedText) {
             setShowImproved(!showImproved)
         } else {
-            setIsImproving(true)
-            try {
-                await AppService.ImproveMessageText(message.id!)
-            } catch (err) {
-                console.error('Failed to improve text:', err)
-                setIsImproving(false)
-            }
+            await improve()
+        }
+    }, [isImproving, hasImprovedText, showImproved, message.id, improve])
+
+    const handleEditStart = useCallback(() => {
+        setEditText(message.text || message.originalText)
+        setIsEditing(true)
+    }, [message.text, message.originalText])
+
+    const handleEditSave = useCallback(async () => {
+        try {
+            await AppService.EditMessageText(message.id!, editText)
+            setIsEditing(false)
+            setShowImproved(true)
+        } catch (err) {
+            console.error('Failed to edit text:', err)
         }
-    }, [isImproving, hasImprovedText, showImproved, message.id])
+    }, [message.id, editText])
+
+    const handleEditKeyDown = (e: React.KeyboardEvent) => {
+        if (e.key === 'Enter' && (e.metaKey || e.ctrlKey)) handleEditSave()
+        if (e.key === 'Escape') setIsEditing(false)
+    }
 
     return (
         <div className="group px-3 py-2">
             <div className="bg-white/5 rounded-xl px-4 py-3 max-w-full">
+                {isEditing && (
+                    <div>
+                        <textarea
+                            value={editText}
+                            onChange={(e) => setEditText(e.target.value)}
+                            onKeyDown={handleEditKeyDown}
+                            autoFocus
+                            rows={6}
+                            className="no-drag w-full px-3 py-2 bg-white/5 border border-white/10 rounded-lg text-sm text-white/90 leading-relaxed focus:outline-none focus:border-white/30 resize-y"
+                        />
+                        <div className="flex justify-end gap-2 mt-2">
+                            <button
+                                onClick={() => setIsEditing(false)}
+                                className="no-drag px-2 py-1 text-xs rounded text-white/50 hover:bg-white/10 transition-colors"
+                            >
+                                Cancel
+                            </button>
+                            <button
+                                onClick={handleEditSave}
+                                className="no-drag px-2 py-1 text-xs rounded bg-white/10 text-white/80 hover:bg-white/20 transition-colors"
+                            >
+                                Save
+                            </button>
+                        </div>
+                    </div>
+                )}
                 <div
+                    hidden={isEditing}
                     ref={textRef}
                     className={`text-sm text-white/85 leading-relaxed select-text overflow-hidden transition-all duration-200 ${
                         !isExpanded && needsExpansion
@@ -114,10 +203,23 @@ export function MessageBubble({ message }: Readonly<Props>) {
                                 : 'none',
                     }}
                 >
-                    {displayText}
+                    {speakerView ? (
+                        <div className="space-y-1.5">
+                            {message.turns!.map((turn, i) => (
+                                <p key={i}>
+                                    <span className="font-medium text-white/50">
+                                        {turn.label}:
+                                    </span>{' '}
+                                    {turn.text}
+                                </p>
+                            ))}
+                        </div>
+                    ) : (
+                        displayText
+                    )}
                 </div>
 
-                {needsExpansion && (
+                {needsExpansion && !isEditing && (
                     <button
                         onClick={() => setIsExpanded(!isExpanded)}
                         className="no-drag flex items-center gap-1 mt-2 text-xs text-white/40 hover:text-white/60 transition-colors"
@@ -151,9 +253,33 @@ export function MessageBubble({ message }: Readonly<Props>) {
                     </div>
 
                     <div className="flex items-center gap-1">
+                        <button
+                            onClick={handleEditStart}
+                            className="no-drag p-1.5 rounded-md hover:bg-white/10 text-white/40 hover:text-white/70 transition-colors"
+                            title="Edit"
+                        >
+                            <LuPencil size={12} />
+                        </button>
+                        <button
+                            onClick={() => setShowHistory(!showHistory)}
+                            className={`no-drag p-1.5 rounded-md hover:bg-white/10 transition-colors ${
+                                showHistory
+                                    ? 'text-white/70'
+                                    : 'text-white/40 hover:text-white/70'
+                            }`}
+                            title="History"
+                        >
+                            <LuHistory size={12} />
+                        </button>
+                        <TransformMenu
+                            templates={transformations.templates}
+                            transformingWith={transformations.transformingWith}
+                            onOpen={transformations.loadTemplates}
+                            onTransform={transformations.transform}
+                            onCancel={transformations.cancel}
+                        />
                         <button
                             onClick={handleSparkleClick}
-                            disabled={isImproving}
                             className={`no-drag p-1.5 rounded-md hover:bg-white/10 transition-colors ${
                                 isImproving
                                     ? 'animate-sparkle text-purple-400'
@@ -163,7 +289,7 @@ export function MessageBubble({ message }: Readonly<Props>) {
                             }`}
                             title={
                                 isImproving
-                                    ? 'Improving...'
+                                    ? 'Improving... click to cancel'
                                     : !hasImprovedText
                                       ? 'Improve text'
                                       : showImproved
@@ -190,6 +316,18 @@ export function MessageBubble({ message }: Readonly<Props>) {
                         </button>
                     </div>
                 </div>
+
+                {showHistory && (
+                    <RevisionHistory
+                        messageId={message.id!}
+                        reloadKey={
+                            isImproving
+                                ? 'improving'
+                                : `${message.text}#${transformCount}`
+                        }
+                        onImprove={improve}
+                    />
+                )}
             </div>
         </div>
     )
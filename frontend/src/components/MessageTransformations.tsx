@@ -0,0 +1,80 @@
+import { useCallback, useState } from 'react'
+import { LuLoader, LuWand } from 'react-icons/lu'
+import type { PromptTemplate } from '../../bindings/mac-dictation/internal/storage'
+
+interface TransformMenuProps {
+    templates: PromptTemplate[]
+    transformingWith: number | null
+    onOpen: () => void
+    onTransform: (templateId: number) => void
+    onCancel: () => void
+}
+
+export function TransformMenu({
+    templates,
+    transformingWith,
+    onOpen,
+    onTransform,
+    onCancel,
+}: Readonly<TransformMenuProps>) {
+    const [open, setOpen] = useState(false)
+
+    const handleToggle = useCallback(() => {
+        if (transformingWith !== null) {
+            onCancel()
+            return
+        }
+        if (!open) onOpen()
+        setOpen(!open)
+    }, [open, transformingWith, onOpen, onCancel])
+
+    const handleSelect = useCallback(
+        (templateId: number) => {
+            setOpen(false)
+            onTransform(templateId)
+        },
+        [onTransform]
+    )
+
+    return (
+        <div className="relative">
+            <button
+                onClick={handleToggle}
+                className={`no-drag p-1.5 rounded-md hover:bg-white/10 transition-colors ${
+                    transformingWith !== null
+                        ? 'text-purple-400'
+                        : 'text-white/40 hover:text-white/70'
+                }`}
+                title={
+                    transformingWith !== null
+                        ? 'Transforming... click to cancel'
+                        : 'Transform'
+                }
+            >
+                {transformingWith !== null ? (
+                    <LuLoader size={12} className="animate-spin" />
+                ) : (
+                    <LuWand size={12} />
+                )}
+            </button>
+            {open && (
+                <div className="absolute right-0 bottom-full mb-1 z-20 min-w-40 py-1 rounded-lg border border-white/10 bg-neutral-900/95 backdrop-blur-sm shadow-lg">
+                    {templates.length === 0 && (
+                        <p className="px-3 py-1.5 text-xs text-white/40">
+                            No templates
+                        </p>
+                    )}
+                    {templates.map((template) => (
+                        <button
+                            key={template.id}
+                            onClick={() => handleSelect(template.id!)}
+                            className="no-drag block w-full text-left px-3 py-1.5 text-xs text-white/70 hover:bg-white/10 transition-colors"
+                        >
+                            {template.name}
+                        </button>
+                    ))}
+                </div>
+            )}
+        </div>
+    )
+}
@@ -1,11 +1,19 @@
 import { useEffect, useState } from 'react'
 import { LuLoader, LuMic, LuSquare, LuTrash2, LuX } from 'react-icons/lu'
 
+// meterWidth maps an RMS level onto a -60dB to 0dB scale, so quiet speech still moves the meter
+function meterWidth(level: number): number {
+    if (level <= 0) return 0
+    return Math.max(0, Math.min(1, 1 + Math.log10(level) / 3))
+}
+
 interface Props {
     isRecording: boolean
     isProcessing: boolean
     hasContent: boolean
     disabled?: boolean
+    // level is the RMS input level (0-1) while recording
+    level?: number
     onStart: () => void
     onStop: () => void
     onCancel: () => void
@@ -17,6 +25,7 @@ export function RecordingControls({
     isProcessing,
     hasContent,
     disabled = false,
+    level = 0,
     onStart,
     onStop,
     onCancel,
@@ -38,6 +47,15 @@ export function RecordingControls({
     if (isRecording) {
         return (
             <div className="flex items-center gap-2">
+                <div
+                    className="w-16 h-1.5 rounded-full bg-white/10 overflow-hidden"
+                    title="Input level"
+                >
+                    <div
+                        className="h-full bg-green-400/80 transition-[width] duration-100"
+                        style={{ width: `${meterWidth(level) * 100}%` }}
+                    />
+                </div>
                 <button
                     onClick={onStop}
                     className="no-drag btn btn-sm btn-error gap-1.5"
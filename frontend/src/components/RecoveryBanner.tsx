@@ -0,0 +1,90 @@
+import { useState, useEffect, useCallback } from 'react'
+import { LuHistory } from 'react-icons/lu'
+import { App as AppService } from '../../bindings/mac-dictation'
+import type { TranscriptionCompletedEvent } from '../types'
+import type { Entry } from '../../bindings/mac-dictation/internal/journal'
+import { useAlerts } from '../contexts/AlertContext'
+
+function formatDuration(secs: number): string {
+    const mins = Math.floor(secs / 60)
+    const rest = Math.round(secs % 60)
+    return mins > 0 ? `${mins}m ${rest}s` : `${rest}s`
+}
+
+interface Props {
+    // onRecovered is called with the saved message, which may belong to a thread other than the active one
+    onRecovered?: (event: TranscriptionCompletedEvent) => void
+}
+
+export function RecoveryBanner({ onRecovered }: Readonly<Props>) {
+    const { addAlert } = useAlerts()
+    const [entries, setEntries] = useState<Entry[]>([])
+    const [busy, setBusy] = useState(false)
+
+    useEffect(() => {
+        AppService.GetOrphanedJournals()
+            .then((found) => setEntries(found ?? []))
+            .catch((err) => console.error('Failed to load unfinished recordings:', err))
+    }, [])
+
+    const entry = entries[0]
+
+    const handleRecover = useCallback(async () => {
+        if (!entry) return
+        setBusy(true)
+        try {
+            const result = await AppService.RecoverJournal(entry.id)
+            if (result) onRecovered?.(result)
+            addAlert('success', 'Recovered unfinished recording')
+            setEntries((prev) => prev.slice(1))
+        } catch (err) {
+            addAlert('error', `Failed to recover recording: ${err}`)
+        } finally {
+            setBusy(false)
+        }
+    }, [entry, addAlert, onRecovered])
+
+    const handleDiscard = useCallback(async () => {
+        if (!entry) return
+        setBusy(true)
+        try {
+            await AppService.DiscardJournal(entry.id)
+            setEntries((prev) => prev.slice(1))
+        } catch (err) {
+            addAlert('error', `Failed to discard recording: ${err}`)
+        } finally {
+            setBusy(false)
+        }
+    }, [entry, addAlert])
+
+    if (!entry) {
+        return null
+    }
+
+    const startedAt = new Date(entry.startedAt).toLocaleString()
+
+    return (
+        <div className="mx-3 mt-2 flex items-center gap-3 p-3 rounded-lg border bg-amber-500/10 border-amber-500/30 text-amber-200">
+            <LuHistory className="w-5 h-5 shrink-0" />
+            <p className="flex-1 text-sm">
+                Unfinished recording from {startedAt} (
+                {formatDuration(entry.durationSecs)})
+                {entries.length > 1 && `, ${entries.length - 1} more`}
+            </p>
+            <button
+                onClick={handleDiscard}
+                disabled={busy}
+                className="shrink-0 px-2 py-1 text-sm rounded hover:bg-white/10 transition-colors disabled:opacity-50"
+            >
+                Discard
+            </button>
+            <button
+                onClick={handleRecover}
+                disabled={busy}
+                className="shrink-0 px-2 py-1 text-sm rounded bg-amber-500/20 hover:bg-amber-500/30 transition-colors disabled:opacity-50"
+            >
+                {busy ? 'Recovering...' : 'Recover'}
+            </button>
+        </div>
+    )
+}
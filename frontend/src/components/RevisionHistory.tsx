@@ -0,0 +1,190 @@
+import { useCallback, useEffect, useState } from 'react'
+import { LuBrush, LuLoader, LuRotateCcw, LuSparkles } from 'react-icons/lu'
+import { App as AppService } from '../../bindings/mac-dictation'
+import {
+    MessageRevision,
+    RevisionSource,
+} from '../../bindings/mac-dictation/internal/storage'
+import { Op, OpType } from '../../bindings/mac-dictation/internal/diff'
+import { useAlerts } from '../contexts/AlertContext'
+
+interface Props {
+    messageId: number
+    // reloadKey changes whenever a revision is added, triggering a reload
+    reloadKey: string
+    onImprove: () => void
+}
+
+const SOURCE_LABELS: Record<string, string> = {
+    transcript: 'Transcript',
+    llm: 'Improved',
+    template: 'Transformed',
+    cleanup: 'Cleaned up',
+    manual: 'Edited',
+    restore: 'Restored',
+}
+
+function formatTime(date: Date): string {
+    return date.toLocaleString('en-GB', {
+        day: 'numeric',
+        month: 'short',
+        hour: '2-digit',
+        minute: '2-digit',
+    })
+}
+
+function DiffView({ ops }: Readonly<{ ops: Op[] }>) {
+    return (
+        <p className="text-xs leading-relaxed text-white/60">
+            {ops.map((op, i) => (
+                <span
+                    key={i}
+                    className={
+                        op.type === OpType.Insert
+                            ? 'bg-green-500/20 text-green-200'
+                            : op.type === OpType.Delete
+                              ? 'bg-red-500/20 text-red-200 line-through'
+                              : undefined
+                    }
+                >
+                    {op.text}{' '}
+                </span>
+            ))}
+        </p>
+    )
+}
+
+export function RevisionHistory({
+    messageId,
+    reloadKey,
+    onImprove,
+}: Readonly<Props>) {
+    const [revisions, setRevisions] = useState<MessageRevision[]>([])
+    const [diffFor, setDiffFor] = useState<number | null>(null)
+    const [diffOps, setDiffOps] = useState<Op[]>([])
+    const [restoring, setRestoring] = useState<number | null>(null)
+    const { addAlert } = useAlerts()
+
+    useEffect(() => {
+        AppService.GetMessageRevisions(messageId)
+            .then((result) => setRevisions(result ?? []))
+            .catch((err) => addAlert('error', `Failed to load history: ${err}`))
+    }, [messageId, reloadKey, addAlert])
+
+    // Template runs are kept alongside the history, the head is the latest other revision
+    const head = [...revisions]
+        .reverse()
+        .find((r) => r.source !== RevisionSource.RevisionTemplate)
+
+    const handleDiff = useCallback(
+        async (revision: MessageRevision) => {
+            if (!head || diffFor === revision.id) {
+                setDiffFor(null)
+                return
+            }
+            try {
+                const ops = await AppService.DiffMessageRevisions(
+                    revision.id!,
+                    head.id!
+                )
+                setDiffOps(ops ?? [])
+                setDiffFor(revision.id!)
+            } catch (err) {
+                addAlert('error', `Failed to compare revisions: ${err}`)
+            }
+        },
+        [head, diffFor, addAlert]
+    )
+
+    const handleRestore = useCallback(
+        async (revisionId: number) => {
+            setRestoring(revisionId)
+            try {
+                await AppService.RestoreMessageRevision(revisionId)
+                setDiffFor(null)
+            } catch (err) {
+                addAlert('error', `Failed to restore revision: ${err}`)
+            } finally {
+                setRestoring(null)
+            }
+        },
+        [addAlert]
+    )
+
+    const handleCleanUp = useCallback(async () => {
+        try {
+            await AppService.CleanMessageText(messageId)
+        } catch (err) {
+            addAlert('error', `Failed to clean up text: ${err}`)
+        }
+    }, [messageId, addAlert])
+
+    return (
+        <div className="mt-2 space-y-1.5">
+            {[...revisions].reverse().map((revision) => {
+                const isHead = revision.id === head?.id
+                return (
+                    <div
+                        key={revision.id}
+                        className="rounded-lg bg-white/5 px-3 py-2"
+                    >
+                        <div className="flex items-center justify-between text-[10px] text-white/30">
+                            <button
+                                onClick={() => handleDiff(revision)}
+                                disabled={isHead}
+                                className="no-drag text-left hover:text-white/60 transition-colors disabled:hover:text-white/30"
+                                title={isHead ? undefined : 'Compare with current'}
+                            >
+                                {SOURCE_LABELS[revision.source] ?? revision.source}
+                                {revision.templateName &&
+                                    ` · ${revision.templateName}`}
+                                {revision.model && ` · ${revision.model}`}
+                                {' · '}
+                                {formatTime(new Date(revision.createdAt))}
+                                {isHead && ' · current'}
+                            </button>
+                            {!isHead && (
+                                <button
+                                    onClick={() => handleRestore(revision.id!)}
+                                    disabled={restoring !== null}
+                                    className="no-drag p-1 rounded hover:bg-white/10 hover:text-white/70 transition-colors"
+                                    title="Restore"
+                                >
+                                    {restoring === revision.id ? (
+                                        <LuLoader size={11} className="animate-spin" />
+                                    ) : (
+                                        <LuRotateCcw size={11} />
+                                    )}
+                                </button>
+                            )}
+                        </div>
+                        {diffFor === revision.id ? (
+                            <DiffView ops={diffOps} />
+                        ) : (
+                            <p className="mt-1 text-xs text-white/60 line-clamp-2">
+                                {revision.text}
+                            </p>
+                        )}
+                    </div>
+                )
+            })}
+            <div className="flex items-center gap-3">
+                <button
+                    onClick={onImprove}
+                    className="no-drag flex items-center gap-1 text-xs text-white/40 hover:text-white/70 transition-colors"
+                >
+                    <LuSparkles size={11} />
+                    Improve again
+                </button>
+                <button
+                    onClick={handleCleanUp}
+                    className="no-drag flex items-center gap-1 text-xs text-white/40 hover:text-white/70 transition-colors"
+                    title="Remove fillers and repeated words without an LLM"
+                >
+                    <LuBrush size={11} />
+                    Quick clean up
+                </button>
+            </div>
+        </div>
+    )
+}
@@ -9,8 +9,9 @@ import {
 } from 'react-icons/lu'
 import { App as AppService } from '../../bindings/mac-dictation'
 import { useAlerts } from '../contexts/AlertContext'
+import { VocabularySettings } from './VocabularySettings'
 
-type SettingType = 'secret' | 'number'
+type SettingType = 'secret' | 'number' | 'text' | 'select'
 
 interface SettingConfig {
     key: string
@@ -21,11 +22,58 @@ interface SettingConfig {
     parse: (value: string) => string | number
     serialize: (value: string | number) => string
     notifyOnChange?: boolean
+    // resets are settings the backend clears when this one changes
+    resets?: string[]
+    // loadOptions lists the choices of a select setting
+    loadOptions?: () => Promise<string[]>
+    // visibleWhen hides settings that only apply to some choices of another setting
+    visibleWhen?: (values: Record<string, SettingValue>) => boolean
 }
 
 type SettingValue = string | number
 
 const SETTINGS: SettingConfig[] = [
+    {
+        key: 'transcription_provider',
+        label: 'Provider',
+        type: 'select',
+        section: 'Transcription',
+        parse: (v) => v || 'deepgram',
+        serialize: String,
+        notifyOnChange: true,
+        loadOptions: async () =>
+            (await AppService.GetTranscriptionProviders()) ?? [],
+    },
+    {
+        key: 'openai_transcription_model',
+        label: 'OpenAI Model',
+        type: 'text',
+        placeholder: 'gpt-4o-transcribe',
+        section: 'Transcription',
+        parse: (v) => v,
+        serialize: String,
+        visibleWhen: (values) => values.transcription_provider === 'openai',
+    },
+    {
+        key: 'local_transcription_url',
+        label: 'Server URL',
+        type: 'text',
+        placeholder: 'http://127.0.0.1:8080/v1',
+        section: 'Transcription',
+        parse: (v) => v,
+        serialize: String,
+        visibleWhen: (values) => values.transcription_provider === 'local',
+    },
+    {
+        key: 'local_transcription_model',
+        label: 'Model',
+        type: 'text',
+        placeholder: 'whisper-1',
+        section: 'Transcription',
+        parse: (v) => v,
+        serialize: String,
+        visibleWhen: (values) => values.transcription_provider === 'local',
+    },
     {
         key: 'deepgram_api_key',
         label: 'Deepgram API Key',
@@ -46,6 +94,52 @@ const SETTINGS: SettingConfig[] = [
         serialize: String,
         notifyOnChange: true,
     },
+    {
+        key: 'anthropic_api_key',
+        label: 'Anthropic API Key',
+        type: 'secret',
+        placeholder: 'Enter your Anthropic API key',
+        section: 'API Keys',
+        parse: (v) => v,
+        serialize: String,
+    },
+    {
+        key: 'llm_provider',
+        label: 'Provider (openai, anthropic or openai-compatible)',
+        type: 'text',
+        placeholder: 'openai',
+        section: 'Text Improvement',
+        parse: (v) => v,
+        serialize: String,
+        resets: ['llm_model'],
+    },
+    {
+        key: 'llm_model',
+        label: 'Model',
+        type: 'text',
+        placeholder: 'Provider default',
+        section: 'Text Improvement',
+        parse: (v) => v,
+        serialize: String,
+    },
+    {
+        key: 'llm_base_url',
+        label: 'Base URL (openai-compatible only)',
+        type: 'text',
+        placeholder: 'http://127.0.0.1:11434/v1',
+        section: 'Text Improvement',
+        parse: (v) => v,
+        serialize: String,
+    },
+    {
+        key: 'llm_api_key',
+        label: 'API Key (openai-compatible only, optional)',
+        type: 'secret',
+        placeholder: 'Not required for Ollama or LM Studio',
+        section: 'Text Improvement',
+        parse: (v) => v,
+        serialize: String,
+    },
     {
         key: 'min_recording_duration',
         label: 'Minimum Recording Duration (seconds)',
@@ -71,6 +165,7 @@ interface SettingInputProps {
     config: SettingConfig
     value: SettingValue
     originalValue: SettingValue
+    options: string[]
     saving: boolean
     onChange: (value: SettingValue) => void
     onSave: () => void
@@ -81,6 +176,7 @@ function SettingInput({
     config,
     value,
     originalValue,
+    options,
     saving,
     onChange,
     onSave,
@@ -90,7 +186,9 @@ function SettingInput({
     const isSecret = config.type === 'secret'
     const isDirty = value !== originalValue
 
-    const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const handleChange = (
+        e: React.ChangeEvent<HTMLInputElement | HTMLSelectElement>
+    ) => {
         const rawValue = e.target.value
         if (config.type === 'number') {
             onChange(rawValue === '' ? '' : Number(rawValue) || 0)
@@ -110,20 +208,35 @@ function SettingInput({
                 {config.label}
             </label>
             <div className="relative">
-                <input
-                    type={
-                        isSecret && !visible
-                            ? 'password'
-                            : config.type === 'number'
-                              ? 'number'
-                              : 'text'
-                    }
-                    value={value}
-                    onChange={handleChange}
-                    onKeyDown={handleKeyDown}
-                    placeholder={config.placeholder}
-                    className="w-full px-3 py-2 pr-24 bg-white/5 border border-white/10 rounded-lg text-white/90 placeholder-white/30 focus:outline-none focus:border-white/30 text-sm font-mono"
-                />
+                {config.type === 'select' ? (
+                    <select
+                        value={value}
+                        onChange={handleChange}
+                        onKeyDown={handleKeyDown}
+                        className="w-full px-3 py-2 pr-24 bg-white/5 border border-white/10 rounded-lg text-white/90 focus:outline-none focus:border-white/30 text-sm font-mono"
+                    >
+                        {options.map((option) => (
+                            <option key={option} value={option}>
+                                {option}
+                            </option>
+                        ))}
+                    </select>
+                ) : (
+                    <input
+                        type={
+                            isSecret && !visible
+                                ? 'password'
+                                : config.type === 'number'
+                                  ? 'number'
+                                  : 'text'
+                        }
+                        value={value}
+                        onChange={handleChange}
+                        onKeyDown={handleKeyDown}
+                        placeholder={config.placeholder}
+                        className="w-full px-3 py-2 pr-24 bg-white/5 border border-white/10 rounded-lg text-white/90 placeholder-white/30 focus:outline-none focus:border-white/30 text-sm font-mono"
+                    />
+                )}
                 <div className="absolute right-2 top-1/2 -translate-y-1/2 flex items-center gap-1">
                     {saving && (
                         <LuLoader
@@ -174,25 +287,37 @@ export function Settings({ onBack, onKeysUpdated }: Readonly<Props>) {
     const [values, setValues] = useState<Record<string, SettingValue>>({})
     const [original, setOriginal] = useState<Record<string, SettingValue>>({})
     const [saving, setSaving] = useState<Record<string, boolean>>({})
+    const [options, setOptions] = useState<Record<string, string[]>>({})
+    const [keysConfigured, setKeysConfigured] = useState(true)
     const [loading, setLoading] = useState(true)
     const { addAlert } = useAlerts()
 
-    const keysConfigured = SETTINGS.filter((s) => s.notifyOnChange).every(
-        (s) => String(values[s.key] ?? '').trim() !== ''
-    )
+    const checkKeys = useCallback(async () => {
+        try {
+            setKeysConfigured(await AppService.AreAPIKeysConfigured())
+        } catch (err) {
+            console.error('Failed to check API keys:', err)
+        }
+    }, [])
 
     useEffect(() => {
         const loadSettings = async () => {
             try {
                 const allSettings = await AppService.GetAllSettings()
                 const initialValues: Record<string, SettingValue> = {}
+                const initialOptions: Record<string, string[]> = {}
                 for (const config of SETTINGS) {
                     initialValues[config.key] = config.parse(
                         allSettings[config.key] ?? ''
                     )
+                    if (config.loadOptions) {
+                        initialOptions[config.key] = await config.loadOptions()
+                    }
                 }
                 setValues(initialValues)
                 setOriginal(initialValues)
+                setOptions(initialOptions)
+                await checkKeys()
             } catch (err) {
                 addAlert('error', `Failed to load settings: ${err}`)
             } finally {
@@ -200,7 +325,7 @@ export function Settings({ onBack, onKeysUpdated }: Readonly<Props>) {
             }
         }
         loadSettings()
-    }, [addAlert])
+    }, [addAlert, checkKeys])
 
     const updateValue = useCallback((key: string, value: SettingValue) => {
         setValues((prev) => ({ ...prev, [key]: value }))
@@ -222,9 +347,20 @@ export function Settings({ onBack, onKeysUpdated }: Readonly<Props>) {
 
             try {
                 await AppService.SetSetting(config.key, config.serialize(value))
-                setOriginal((prev) => ({ ...prev, [config.key]: value }))
+                const cleared: Record<string, SettingValue> = {}
+                for (const key of config.resets ?? []) {
+                    const reset = SETTINGS.find((s) => s.key === key)
+                    if (reset) cleared[key] = reset.parse('')
+                }
+                setValues((prev) => ({ ...prev, ...cleared }))
+                setOriginal((prev) => ({
+                    ...prev,
+                    ...cleared,
+                    [config.key]: value,
+                }))
                 addAlert('success', `${config.label} saved`)
                 if (config.notifyOnChange) {
+                    await checkKeys()
                     onKeysUpdated?.()
                 }
             } catch (err) {
@@ -233,7 +369,7 @@ export function Settings({ onBack, onKeysUpdated }: Readonly<Props>) {
                 setSaving((prev) => ({ ...prev, [config.key]: false }))
             }
         },
-        [values, original, onKeysUpdated, addAlert]
+        [values, original, checkKeys, onKeysUpdated, addAlert]
     )
 
     if (loading) {
@@ -260,7 +396,8 @@ export function Settings({ onBack, onKeysUpdated }: Readonly<Props>) {
                 {!keysConfigured && (
                     <div className="mb-6 p-3 bg-amber-500/10 border border-amber-500/20 rounded-lg">
                         <p className="text-sm text-amber-200/80">
-                            Configure both API keys to enable recording.
+                            Configure the API key for the selected
+                            transcription provider to enable recording.
                         </p>
                     </div>
                 )}
@@ -276,13 +413,16 @@ export function Settings({ onBack, onKeysUpdated }: Readonly<Props>) {
                             </h2>
                             <div className="space-y-4">
                                 {SETTINGS.filter(
-                                    (s) => s.section === section
+                                    (s) =>
+                                        s.section === section &&
+                                        (s.visibleWhen?.(values) ?? true)
                                 ).map((config) => (
                                     <SettingInput
                                         key={config.key}
                                         config={config}
                                         value={values[config.key]}
                                         originalValue={original[config.key]}
+                                        options={options[config.key] ?? []}
                                         saving={saving[config.key] ?? false}
                                         onChange={(value) =>
                                             updateValue(config.key, value)
@@ -294,6 +434,7 @@ export function Settings({ onBack, onKeysUpdated }: Readonly<Props>) {
                             </div>
                         </div>
                     ))}
+                    <VocabularySettings />
                 </div>
             </div>
         </div>
@@ -0,0 +1,180 @@
+import { useCallback, useEffect, useState } from 'react'
+import { LuCheck, LuPlus, LuTrash2, LuX } from 'react-icons/lu'
+import { App as AppService } from '../../bindings/mac-dictation'
+import type { VocabularyTerm } from '../../bindings/mac-dictation/internal/storage'
+import { useAlerts } from '../contexts/AlertContext'
+
+interface TermRowProps {
+    term: VocabularyTerm
+    onUpdate: (id: number, term: string) => Promise<boolean>
+    onDelete: (id: number) => void
+}
+
+function TermRow({ term, onUpdate, onDelete }: Readonly<TermRowProps>) {
+    const [editing, setEditing] = useState(false)
+    const [value, setValue] = useState(term.term)
+
+    const cancel = useCallback(() => {
+        setValue(term.term)
+        setEditing(false)
+    }, [term.term])
+
+    const save = useCallback(async () => {
+        if (value.trim() === term.term) {
+            setEditing(false)
+            return
+        }
+        if (await onUpdate(term.id!, value)) {
+            setEditing(false)
+        }
+    }, [value, term.id, term.term, onUpdate])
+
+    const handleKeyDown = (e: React.KeyboardEvent) => {
+        if (e.key === 'Enter') save()
+        if (e.key === 'Escape') cancel()
+    }
+
+    return (
+        <li className="group flex items-center gap-1 rounded-lg bg-white/5 px-3 py-1.5">
+            {editing ? (
+                <>
+                    <input
+                        value={value}
+                        onChange={(e) => setValue(e.target.value)}
+                        onKeyDown={handleKeyDown}
+                        autoFocus
+                        className="flex-1 bg-transparent text-sm text-white/90 font-mono focus:outline-none"
+                    />
+                    <button
+                        onClick={save}
+                        className="p-1 rounded hover:bg-green-500/20 text-green-400 hover:text-green-300 transition-colors"
+                        title="Save"
+                    >
+                        <LuCheck size={12} />
+                    </button>
+                    <button
+                        onClick={cancel}
+                        className="p-1 rounded hover:bg-white/10 text-white/40 hover:text-white/60 transition-colors"
+                        title="Cancel"
+                    >
+                        <LuX size={12} />
+                    </button>
+                </>
+            ) : (
+                <>
+                    <button
+                        onClick={() => setEditing(true)}
+                        className="flex-1 text-left text-sm text-white/80 font-mono"
+                        title="Edit"
+                    >
+                        {term.term}
+                    </button>
+                    <button
+                        onClick={() => onDelete(term.id!)}
+                        className="p-1 rounded opacity-0 group-hover:opacity-100 hover:bg-white/10 text-white/40 hover:text-red-300 transition-all"
+                        title="Delete"
+                    >
+                        <LuTrash2 size={12} />
+                    </button>
+                </>
+            )}
+        </li>
+    )
+}
+
+// VocabularySettings manages the custom vocabulary used to boost recognition
+// of names and jargon during transcription
+export function VocabularySettings() {
+    const [terms, setTerms] = useState<VocabularyTerm[]>([])
+    const [newTerm, setNewTerm] = useState('')
+    const { addAlert } = useAlerts()
+
+    const load = useCallback(async () => {
+        try {
+            setTerms((await AppService.GetVocabulary()) ?? [])
+        } catch (err) {
+            addAlert('error', `Failed to load vocabulary: ${err}`)
+        }
+    }, [addAlert])
+
+    useEffect(() => {
+        load()
+    }, [load])
+
+    const handleAdd = useCallback(async () => {
+        if (newTerm.trim() === '') return
+        try {
+            await AppService.AddVocabularyTerm(newTerm)
+            setNewTerm('')
+            await load()
+        } catch (err) {
+            addAlert('error', `Failed to add term: ${err}`)
+        }
+    }, [newTerm, load, addAlert])
+
+    const handleUpdate = useCallback(
+        async (id: number, term: string) => {
+            try {
+                await AppService.UpdateVocabularyTerm(id, term)
+                await load()
+                return true
+            } catch (err) {
+                addAlert('error', `Failed to update term: ${err}`)
+                return false
+            }
+        },
+        [load, addAlert]
+    )
+
+    const handleDelete = useCallback(
+        async (id: number) => {
+            try {
+                await AppService.DeleteVocabularyTerm(id)
+                setTerms((prev) => prev.filter((t) => t.id !== id))
+            } catch (err) {
+                addAlert('error', `Failed to delete term: ${err}`)
+            }
+        },
+        [addAlert]
+    )
+
+    return (
+        <div>
+            <h2 className="text-sm font-medium text-white/50 uppercase tracking-wider mb-2">
+                Vocabulary
+            </h2>
+            <p className="text-xs text-white/40 mb-4">
+                Names and terms the transcriber should listen for.
+            </p>
+            <div className="flex items-center gap-2 mb-3">
+                <input
+                    value={newTerm}
+                    onChange={(e) => setNewTerm(e.target.value)}
+                    onKeyDown={(e) => e.key === 'Enter' && handleAdd()}
+                    placeholder="Add a word or phrase"
+                    className="flex-1 px-3 py-2 bg-white/5 border border-white/10 rounded-lg text-white/90 placeholder-white/30 focus:outline-none focus:border-white/30 text-sm font-mono"
+                />
+                <button
+                    onClick={handleAdd}
+                    disabled={newTerm.trim() === ''}
+                    className="p-2 rounded-lg bg-white/10 text-white/70 hover:bg-white/20 disabled:opacity-40 transition-colors"
+                    title="Add"
+                >
+                    <LuPlus size={14} />
+                </button>
+            </div>
+            {terms.length > 0 && (
+                <ul className="space-y-1">
+                    {terms.map((term) => (
+                        <TermRow
+                            key={term.id}
+                            term={term}
+                            onUpdate={handleUpdate}
+                            onDelete={handleDelete}
+                        />
+                    ))}
+                </ul>
+            )}
+        </div>
+    )
+}
@@ -8,3 +8,4 @@ export { TitleBar } from './TitleBar'
 export { ThreadHeader } from './ThreadHeader'
 export { Settings } from './Settings'
 export { AlertToast } from './AlertToast'
+export { RecoveryBanner } from './RecoveryBanner'
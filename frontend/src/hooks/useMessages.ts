@@ -1,4 +1,4 @@
-import { useCallback, useEffect, useState } from 'react'
+import { useCallback, useEffect, useRef, useState } from 'react'
 import { Events } from '@wailsio/runtime'
 import { App as AppService } from '../../bindings/mac-dictation'
 import { Message } from '../../bindings/mac-dictation/internal/storage'
@@ -17,9 +17,21 @@ interface TextImprovedEvent {
     improvedText: string
 }
 
+interface TextImprovingEvent {
+    messageId: number
+    delta: string
+}
+
+interface TextUpdatedEvent {
+    messageId: number
+    text: string
+}
+
 export function useMessages(threadId: number | null) {
     const [messages, setMessages] = useState<Message[]>([])
     const [loading, setLoading] = useState(false)
+    // streaming holds messages receiving improved text, the first delta replaces the current text
+    const streaming = useRef(new Set<number>())
     const { addAlert } = useAlerts()
 
     const fetchMessages = useCallback(
@@ -51,6 +63,7 @@ export function useMessages(threadId: number | null) {
             'message:text-improved',
             (ev: Events.WailsEvent) => {
                 const data = ev.data as TextImprovedEvent
+                streaming.current.delete(data.messageId)
                 setMessages((prev) =>
                     prev.map((msg) => {
                         if (msg.id === data.messageId) {
@@ -64,6 +77,45 @@ export function useMessages(threadId: number | null) {
         return () => unsub()
     }, [])
 
+    useEffect(() => {
+        const unsub = Events.On(
+            'message:text-improving',
+            (ev: Events.WailsEvent) => {
+                const data = ev.data as TextImprovingEvent
+                const started = streaming.current.has(data.messageId)
+                streaming.current.add(data.messageId)
+                setMessages((prev) =>
+                    prev.map((msg) => {
+                        if (msg.id === data.messageId) {
+                            const text = started ? msg.text + data.delta : data.delta
+                            return { ...msg, text }
+                        }
+                        return msg
+                    })
+                )
+            }
+        )
+        return () => unsub()
+    }, [])
+
+    useEffect(() => {
+        const unsub = Events.On(
+            'message:text-updated',
+            (ev: Events.WailsEvent) => {
+                const data = ev.data as TextUpdatedEvent
+                setMessages((prev) =>
+                    prev.map((msg) => {
+                        if (msg.id === data.messageId) {
+                            return { ...msg, text: data.text }
+                        }
+                        return msg
+                    })
+                )
+            }
+        )
+        return () => unsub()
+    }, [])
+
     const addMessage = useCallback((message: Message) => {
         setMessages((prev) => [...prev, parseMessageDates(message)])
     }, [])
@@ -2,10 +2,27 @@ import { useCallback, useEffect, useRef, useState } from 'react'
 import { Events } from '@wailsio/runtime'
 import { App as AppService } from '../../bindings/mac-dictation'
 import { config } from '../config'
+import { useAlerts } from '../contexts/AlertContext'
 import type { TranscriptionCompletedEvent } from '../types'
 
 type RecordingState = 'idle' | 'recording' | 'processing'
 
+interface RecordingProgress {
+    durationSecs: number
+    level: number
+    peak: number
+}
+
+interface SilenceWarning {
+    secondsRemaining: number
+}
+
+interface RecordingTooShort {
+    durationSecs: number
+    minDurationSecs: number
+    keptAsDraft: boolean
+}
+
 interface InterimTranscript {
     text: string
     isFinal: boolean
@@ -27,12 +44,14 @@ interface UseRecordingOptions {
 export function useRecording(options: UseRecordingOptions = {}) {
     const [state, setState] = useState<RecordingState>('idle')
     const [durationSecs, setDurationSecs] = useState(0)
+    const [level, setLevel] = useState(0)
     const [copied, setCopied] = useState(false)
     const [lastTranscript, setLastTranscript] = useState('')
     const [interimTranscript, setInterimTranscript] = useState('')
     const finalizedTextRef = useRef('')
     const copyTimeoutRef = useRef<number | null>(null)
     const optionsRef = useRef(options)
+    const { addAlert } = useAlerts()
 
     useEffect(() => {
         optionsRef.current = options
@@ -43,12 +62,40 @@ export function useRecording(options: UseRecordingOptions = {}) {
             Events.On('recording:started', () => {
                 setState('recording')
                 setDurationSecs(0)
+                setLevel(0)
                 setCopied(false)
                 setInterimTranscript('')
                 finalizedTextRef.current = ''
             }),
             Events.On('recording:progress', (ev: Events.WailsEvent) => {
-                setDurationSecs(ev.data as number)
+                const data = ev.data as RecordingProgress
+                setDurationSecs(data.durationSecs)
+                setLevel(data.level)
+            }),
+            Events.On(
+                'recording:silence-warning',
+                (ev: Events.WailsEvent) => {
+                    const data = ev.data as SilenceWarning
+                    addAlert(
+                        'warning',
+                        `No speech detected, stopping in ${Math.ceil(data.secondsRemaining)}s`
+                    )
+                }
+            ),
+            Events.On('recording:too-short', (ev: Events.WailsEvent) => {
+                const data = ev.data as RecordingTooShort
+                const minimum = `${data.minDurationSecs}s minimum`
+                if (data.keptAsDraft) {
+                    addAlert(
+                        'info',
+                        `Recording was shorter than the ${minimum}, kept as a draft`
+                    )
+                } else {
+                    addAlert(
+                        'warning',
+                        `Recording was shorter than the ${minimum} and was discarded`
+                    )
+                }
             }),
             Events.On('recording:stopped', () => {
                 setState((current) =>
@@ -71,6 +118,19 @@ export function useRecording(options: UseRecordingOptions = {}) {
                     setInterimTranscript(display)
                 }
             }),
+            Events.On('recording:rolled-over', (ev: Events.WailsEvent) => {
+                const data = ev.data as TranscriptionCompletedEvent
+                setInterimTranscript('')
+                finalizedTextRef.current = ''
+                optionsRef.current.onTranscriptionComplete?.(data)
+            }),
+            Events.On('state:changed', (ev: Events.WailsEvent) => {
+                const data = ev.data as { state: string }
+                // Covers cancelled recordings, which never complete a transcription
+                if (data.state === 'idle') {
+                    setState('idle')
+                }
+            }),
             Events.On('transcription:processing', () => {
                 setState('processing')
             }),
@@ -113,7 +173,7 @@ export function useRecording(options: UseRecordingOptions = {}) {
             unsubs.forEach((fn) => fn())
             if (copyTimeoutRef.current) clearTimeout(copyTimeoutRef.current)
         }
-    }, [])
+    }, [addAlert])
 
     const startRecording = useCallback(() => AppService.StartRecording(), [])
     const stopRecording = useCallback(() => AppService.StopRecording(), [])
@@ -136,6 +196,7 @@ export function useRecording(options: UseRecordingOptions = {}) {
     return {
         state,
         durationSecs,
+        level,
         copied,
         lastTranscript,
         interimTranscript,
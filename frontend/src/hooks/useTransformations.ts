@@ -0,0 +1,58 @@
+import { useCallback, useState } from 'react'
+import { App as AppService } from '../../bindings/mac-dictation'
+import type { PromptTemplate } from '../../bindings/mac-dictation/internal/storage'
+import { useAlerts } from '../contexts/AlertContext'
+
+// onTransformed is called once a transformation is saved to the message's history
+export function useTransformations(
+    messageId: number | null,
+    onTransformed?: () => void
+) {
+    const [templates, setTemplates] = useState<PromptTemplate[]>([])
+    const [transformingWith, setTransformingWith] = useState<number | null>(
+        null
+    )
+    const { addAlert } = useAlerts()
+
+    const loadTemplates = useCallback(async () => {
+        try {
+            const result = await AppService.GetPromptTemplates()
+            setTemplates(result ?? [])
+        } catch (err) {
+            addAlert('error', `Failed to load templates: ${err}`)
+        }
+    }, [addAlert])
+
+    const transform = useCallback(
+        async (templateId: number) => {
+            if (messageId === null || transformingWith !== null) return
+            setTransformingWith(templateId)
+            try {
+                // The result is saved as a revision without replacing the text
+                const result = await AppService.TransformMessage(
+                    messageId,
+                    templateId
+                )
+                if (result) onTransformed?.()
+            } catch (err) {
+                addAlert('error', `Failed to transform message: ${err}`)
+            } finally {
+                setTransformingWith(null)
+            }
+        },
+        [messageId, transformingWith, onTransformed, addAlert]
+    )
+
+    const cancel = useCallback(async () => {
+        if (messageId === null) return
+        await AppService.CancelTransformMessage(messageId)
+    }, [messageId])
+
+    return {
+        templates,
+        transformingWith,
+        loadTemplates,
+        transform,
+        cancel,
+    }
+}
@@ -1,7 +1,9 @@
 package audio
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"sync"
 	"time"
@@ -17,10 +19,31 @@ type Recorder struct {
 	malgoCtx       *malgo.AllocatedContext
 	device         *malgo.Device
 	onChunk        func([]byte)
+	// journal receives a copy of captured audio so it survives a crash
+	journal io.Writer
+
+	// inputDevice is the capture device to record from, nil uses the system default
+	inputDevice *malgo.DeviceID
+
+	// levelRMS and levelPeak hold the levels of the most recently captured chunk
+	levelRMS  float64
+	levelPeak float64
+
+	vad VAD
+}
+
+// InputDevice describes a capture device available to the recorder
+type InputDevice struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	IsDefault bool   `json:"isDefault"`
 }
 
+var ErrInputDeviceNotFound = errors.New("input device not found")
+
 const (
 	SampleRate     = 16000
+	Channels       = 1
 	BytesPerSample = 2
 	BytesPerSecond = SampleRate * BytesPerSample
 )
@@ -44,6 +67,88 @@ func (r *Recorder) SetOnChunk(onChunk func([]byte)) {
 	r.onChunk = onChunk
 }
 
+// SetJournal sets where captured audio is journaled, nil disables journaling.
+// The journal is cleared when the recording stops or is cancelled.
+func (r *Recorder) SetJournal(journal io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.journal = journal
+}
+
+// ListInputDevices returns all capture devices currently available
+func (r *Recorder) ListInputDevices() ([]InputDevice, error) {
+	infos, err := r.captureDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]InputDevice, 0, len(infos))
+	for _, info := range infos {
+		devices = append(devices, InputDevice{
+			ID:        info.ID.String(),
+			Name:      info.Name(),
+			IsDefault: info.IsDefault != 0,
+		})
+	}
+	return devices, nil
+}
+
+// SetInputDevice selects the capture device used by subsequent recordings.
+//
+// An empty id selects the system default. If the device cannot be found the
+// recorder falls back to the system default and ErrInputDeviceNotFound is returned
+func (r *Recorder) SetInputDevice(id string) error {
+	if id == "" {
+		r.mu.Lock()
+		r.inputDevice = nil
+		r.mu.Unlock()
+		return nil
+	}
+
+	infos, err := r.captureDevices()
+	if err != nil {
+		return err
+	}
+
+	var selected *malgo.DeviceID
+	for _, info := range infos {
+		if info.ID.String() == id {
+			deviceID := info.ID
+			selected = &deviceID
+			break
+		}
+	}
+
+	r.mu.Lock()
+	r.inputDevice = selected
+	r.mu.Unlock()
+
+	if selected == nil {
+		return fmt.Errorf("%w: %s", ErrInputDeviceNotFound, id)
+	}
+	return nil
+}
+
+// SetSilenceThreshold sets the RMS level (0-1) below which captured audio is
+// considered silence when calculating RecordingStatus.SilenceSecs
+func (r *Recorder) SetSilenceThreshold(threshold float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.vad.Threshold = threshold
+}
+
+func (r *Recorder) captureDevices() ([]malgo.DeviceInfo, error) {
+	if r.malgoCtx == nil {
+		return nil, fmt.Errorf("audio context not initialized")
+	}
+
+	infos, err := r.malgoCtx.Devices(malgo.Capture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list capture devices: %w", err)
+	}
+	return infos, nil
+}
+
 func (r *Recorder) StartRecording() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -58,20 +163,35 @@ func (r *Recorder) StartRecording() error {
 
 	r.audioBuffer = make([]byte, 0)
 	r.recordingStart = time.Now()
+	r.levelRMS, r.levelPeak = 0, 0
+	r.vad.Reset(r.recordingStart)
 
 	// Config is 16kHz mono PCM16
 	deviceConfig := malgo.DefaultDeviceConfig(malgo.Capture)
 	deviceConfig.Capture.Format = malgo.FormatS16
-	deviceConfig.Capture.Channels = 1
+	deviceConfig.Capture.Channels = Channels
 	deviceConfig.SampleRate = SampleRate
 	deviceConfig.Alsa.NoMMap = 1
+	if r.inputDevice != nil {
+		deviceConfig.Capture.DeviceID = r.inputDevice.Pointer()
+	}
 
 	onRecvFrames := func(pOutputSample, pInputSamples []byte, framecount uint32) {
+		rms, peak := Levels(pInputSamples)
+
 		r.mu.Lock()
 		r.audioBuffer = append(r.audioBuffer, pInputSamples...)
+		r.levelRMS, r.levelPeak = rms, peak
+		r.vad.Process(rms, time.Now())
 		callback := r.onChunk
+		journal := r.journal
 		r.mu.Unlock()
 
+		if journal != nil {
+			if _, err := journal.Write(pInputSamples); err != nil {
+				slog.Error("failed to journal audio", "error", err)
+			}
+		}
 		if callback != nil {
 			callback(pInputSamples)
 		}
@@ -100,9 +220,9 @@ func (r *Recorder) StartRecording() error {
 
 func (r *Recorder) StopRecording() ([]byte, error) {
 	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	if !r.isRecording {
-		r.mu.Unlock()
 		return nil, fmt.Errorf("not recording")
 	}
 
@@ -117,9 +237,9 @@ func (r *Recorder) StopRecording() ([]byte, error) {
 	}
 
 	r.isRecording = false
+	r.journal = nil
 	audioData := r.audioBuffer
 	r.audioBuffer = nil
-	r.mu.Unlock()
 
 	if len(audioData) == 0 {
 		return nil, fmt.Errorf("no audio recorded")
@@ -128,6 +248,21 @@ func (r *Recorder) StopRecording() ([]byte, error) {
 	return audioData, nil
 }
 
+// TakeAudio returns the audio captured so far and starts a fresh buffer
+// without interrupting the recording. Audio captured afterwards is journaled
+// to journal, so no frame lands in one buffer but the other journal.
+func (r *Recorder) TakeAudio(journal io.Writer) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	audioData := r.audioBuffer
+	if r.isRecording {
+		r.audioBuffer = make([]byte, 0, len(audioData))
+		r.journal = journal
+	}
+	return audioData
+}
+
 func (r *Recorder) CancelRecording() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -147,6 +282,7 @@ func (r *Recorder) CancelRecording() error {
 	}
 
 	r.isRecording = false
+	r.journal = nil
 	r.audioBuffer = nil
 
 	return nil
@@ -155,21 +291,29 @@ func (r *Recorder) CancelRecording() error {
 type RecordingStatus struct {
 	IsRecording  bool    `json:"is_recording"`
 	DurationSecs float64 `json:"duration_secs"`
+	// Level and Peak are the RMS and peak amplitude (0-1) of the latest captured chunk
+	Level float64 `json:"level"`
+	Peak  float64 `json:"peak"`
+	// SilenceSecs is how long the input has been below the silence threshold
+	SilenceSecs float64 `json:"silence_secs"`
+	// BufferedBytes is the amount of audio held since the recording started or audio was last taken
+	BufferedBytes int `json:"buffered_bytes"`
 }
 
 func (r *Recorder) GetStatus() RecordingStatus {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	var duration float64
+	status := RecordingStatus{IsRecording: r.isRecording}
 	if r.isRecording {
-		duration = time.Since(r.recordingStart).Seconds()
+		status.DurationSecs = time.Since(r.recordingStart).Seconds()
+		status.Level = r.levelRMS
+		status.Peak = r.levelPeak
+		status.SilenceSecs = r.vad.Silence(time.Now()).Seconds()
+		status.BufferedBytes = len(r.audioBuffer)
 	}
 
-	return RecordingStatus{
-		IsRecording:  r.isRecording,
-		DurationSecs: duration,
-	}
+	return status
 }
 
 func (r *Recorder) Shutdown() error {
@@ -0,0 +1,23 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Levels calculates the RMS and peak amplitude of PCM16 samples, normalised to 0-1
+func Levels(pcm []byte) (rms float64, peak float64) {
+	samples := len(pcm) / BytesPerSample
+	if samples == 0 {
+		return 0, 0
+	}
+
+	var sumSquares float64
+	for i := 0; i+1 < len(pcm); i += BytesPerSample {
+		sample := float64(int16(binary.LittleEndian.Uint16(pcm[i:]))) / math.MaxInt16
+		sumSquares += sample * sample
+		peak = max(peak, math.Abs(sample))
+	}
+
+	return math.Sqrt(sumSquares / float64(samples)), min(peak, 1)
+}
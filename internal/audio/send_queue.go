@@ -0,0 +1,178 @@
+package audio
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// OverflowPolicy decides which audio is lost when the SendQueue is full
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest queued audio to make room for new audio
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards incoming audio until the queue has room
+	DropNewest
+)
+
+// QueueStats reports the state of a SendQueue
+type QueueStats struct {
+	DepthBytes   int   `json:"depthBytes"`
+	DroppedBytes int64 `json:"droppedBytes"`
+}
+
+// SendQueue decouples the capture callback from network writes. Push copies
+// audio into a bounded ring buffer without blocking, and a sender goroutine
+// coalesces it into fixed size chunks before calling send.
+type SendQueue struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	ring  []byte
+	head  int
+	size  int
+	chunk int
+
+	policy  OverflowPolicy
+	dropped int64
+	closed  bool
+
+	// Positions count bytes through the queue since it was created. headPos is
+	// where the ring starts, sendingPos where the chunk being sent starts, and
+	// flushPos how far the newest Flush needs sent. The sender sends a partial
+	// chunk only to reach flushPos, so audio pushed after a flush still coalesces.
+	headPos    int64
+	sendingPos int64
+	sending    bool
+	flushPos   int64
+
+	send func([]byte)
+	done chan struct{}
+}
+
+// NewSendQueue creates a queue holding up to capacity bytes, sending chunkSize byte chunks
+func NewSendQueue(capacity, chunkSize int, policy OverflowPolicy, send func([]byte)) *SendQueue {
+	q := &SendQueue{
+		ring:   make([]byte, capacity),
+		chunk:  chunkSize,
+		policy: policy,
+		send:   send,
+		done:   make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// Push queues audio for sending. It never blocks on the network so is safe
+// to call from the audio callback.
+func (q *SendQueue) Push(data []byte) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	if overflow := q.size + len(data) - len(q.ring); overflow > 0 {
+		switch q.policy {
+		case DropNewest:
+			q.dropped += int64(overflow)
+			data = data[:len(data)-overflow]
+		default:
+			// Anything larger than the whole ring can only keep its tail
+			if len(data) > len(q.ring) {
+				q.dropped += int64(len(data) - len(q.ring))
+				data = data[len(data)-len(q.ring):]
+				overflow = q.size + len(data) - len(q.ring)
+			}
+			q.dropped += int64(overflow)
+			q.head = (q.head + overflow) % len(q.ring)
+			q.size -= overflow
+			q.headPos += int64(overflow)
+			// Dropped audio no longer holds up a flush
+			q.cond.Broadcast()
+		}
+		slog.Warn("Audio send queue overflow", "droppedBytes", overflow, "totalDroppedBytes", q.dropped)
+	}
+
+	tail := (q.head + q.size) % len(q.ring)
+	n := copy(q.ring[tail:], data)
+	copy(q.ring, data[n:])
+	q.size += len(data)
+
+	if q.size >= q.chunk {
+		q.cond.Broadcast()
+	}
+}
+
+// Flush blocks until all audio queued before the call, including any partial
+// chunk, has been sent. Audio pushed while it waits is not waited for.
+func (q *SendQueue) Flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	target := q.headPos + int64(q.size)
+	if target > q.flushPos {
+		q.flushPos = target
+		q.cond.Broadcast()
+	}
+	for q.headPos < target || (q.sending && q.sendingPos < target) {
+		q.cond.Wait()
+	}
+}
+
+// Close sends the remaining audio and stops the sender goroutine. Audio pushed after Close is discarded.
+func (q *SendQueue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		q.cond.Broadcast()
+	}
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+func (q *SendQueue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return QueueStats{DepthBytes: q.size, DroppedBytes: q.dropped}
+}
+
+func (q *SendQueue) run() {
+	defer close(q.done)
+
+	for {
+		q.mu.Lock()
+		for q.size < q.chunk && q.flushPos <= q.headPos && !q.closed {
+			q.cond.Wait()
+		}
+		if q.size == 0 {
+			q.mu.Unlock()
+			return
+		}
+
+		n := min(q.chunk, q.size)
+		if q.size < q.chunk && !q.closed {
+			// Only flushing sends a partial chunk, and only up to the flushed audio
+			n = min(n, int(q.flushPos-q.headPos))
+		}
+		chunk := make([]byte, n)
+		copied := copy(chunk, q.ring[q.head:min(q.head+n, len(q.ring))])
+		copy(chunk[copied:], q.ring)
+		q.head = (q.head + n) % len(q.ring)
+		q.size -= n
+		q.sendingPos = q.headPos
+		q.headPos += int64(n)
+		q.sending = true
+		q.mu.Unlock()
+
+		q.send(chunk)
+
+		q.mu.Lock()
+		q.sending = false
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+}
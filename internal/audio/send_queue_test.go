@@ -0,0 +1,128 @@
+package audio
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSender collects the size of every chunk sent, optionally slowly
+type recordingSender struct {
+	mu    sync.Mutex
+	sizes []int
+	delay time.Duration
+	total int
+}
+
+func (s *recordingSender) send(chunk []byte) {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sizes = append(s.sizes, len(chunk))
+	s.total += len(chunk)
+}
+
+func (s *recordingSender) sent() (int, []int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total, append([]int(nil), s.sizes...)
+}
+
+func TestSendQueueFlushSendsPartialChunk(t *testing.T) {
+	sender := &recordingSender{}
+	q := NewSendQueue(1024, 100, DropOldest, sender.send)
+	defer q.Close()
+
+	q.Push(make([]byte, 250))
+	q.Flush()
+
+	total, sizes := sender.sent()
+	if total != 250 {
+		t.Fatalf("sent %d bytes, want 250", total)
+	}
+	if want := []int{100, 100, 50}; !slices.Equal(sizes, want) {
+		t.Fatalf("sent chunks %v, want %v", sizes, want)
+	}
+}
+
+func TestSendQueueOverlappingFlushes(t *testing.T) {
+	sender := &recordingSender{delay: 5 * time.Millisecond}
+	q := NewSendQueue(64*1024, 100, DropOldest, sender.send)
+	defer q.Close()
+
+	stop := make(chan struct{})
+	var capture sync.WaitGroup
+	capture.Add(1)
+	go func() {
+		defer capture.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				q.Push(make([]byte, 30))
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	var flushes sync.WaitGroup
+	for range 8 {
+		flushes.Add(1)
+		go func() {
+			defer flushes.Done()
+			q.Flush()
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		flushes.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("overlapping flushes did not return while capture continued")
+	}
+	close(stop)
+	capture.Wait()
+}
+
+func TestSendQueueCoalescesAfterFlush(t *testing.T) {
+	sender := &recordingSender{}
+	q := NewSendQueue(1024, 100, DropOldest, sender.send)
+
+	q.Push(make([]byte, 40))
+	q.Flush()
+	for range 10 {
+		q.Push(make([]byte, 30))
+	}
+	q.Close()
+
+	total, sizes := sender.sent()
+	if total != 340 {
+		t.Fatalf("sent %d bytes, want 340", total)
+	}
+	if want := []int{40, 100, 100, 100}; !slices.Equal(sizes, want) {
+		t.Fatalf("sent chunks %v, want %v", sizes, want)
+	}
+}
+
+func TestSendQueueDropOldest(t *testing.T) {
+	sender := &recordingSender{}
+	q := NewSendQueue(100, 1000, DropOldest, sender.send)
+
+	q.Push(make([]byte, 80))
+	q.Push(make([]byte, 50))
+	if got := q.Stats(); got.DepthBytes != 100 || got.DroppedBytes != 30 {
+		t.Fatalf("stats %+v, want depth 100 and 30 dropped", got)
+	}
+	q.Close()
+
+	if total, _ := sender.sent(); total != 100 {
+		t.Fatalf("sent %d bytes, want 100", total)
+	}
+}
@@ -0,0 +1,31 @@
+package audio
+
+import "time"
+
+// VAD is an energy based voice activity detector. Chunks with an RMS level at
+// or above Threshold are treated as voice, everything else as silence.
+type VAD struct {
+	Threshold float64
+
+	lastVoice time.Time
+}
+
+// Reset starts tracking silence from now
+func (v *VAD) Reset(now time.Time) {
+	v.lastVoice = now
+}
+
+// Process records the level of a captured chunk
+func (v *VAD) Process(rms float64, now time.Time) {
+	if rms >= v.Threshold {
+		v.lastVoice = now
+	}
+}
+
+// Silence returns how long it has been since voice was last detected
+func (v *VAD) Silence(now time.Time) time.Duration {
+	if v.lastVoice.IsZero() {
+		return 0
+	}
+	return now.Sub(v.lastVoice)
+}
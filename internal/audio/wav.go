@@ -0,0 +1,46 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const wavHeaderSize = 44
+
+// EncodeWAV wraps raw 16kHz mono PCM16 audio in a WAV container
+func EncodeWAV(pcm []byte) []byte {
+	buf := make([]byte, wavHeaderSize+len(pcm))
+
+	copy(buf[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(36+len(pcm)))
+	copy(buf[8:12], "WAVE")
+
+	copy(buf[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:20], 16)
+	binary.LittleEndian.PutUint16(buf[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(buf[22:24], Channels)
+	binary.LittleEndian.PutUint32(buf[24:28], SampleRate)
+	binary.LittleEndian.PutUint32(buf[28:32], BytesPerSecond)
+	binary.LittleEndian.PutUint16(buf[32:34], Channels*BytesPerSample)
+	binary.LittleEndian.PutUint16(buf[34:36], BytesPerSample*8)
+
+	copy(buf[36:40], "data")
+	binary.LittleEndian.PutUint32(buf[40:44], uint32(len(pcm)))
+	copy(buf[wavHeaderSize:], pcm)
+
+	return buf
+}
+
+// WriteWAV writes raw PCM16 audio to path as a WAV file, creating parent directories as needed
+func WriteWAV(path string, pcm []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create audio directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, EncodeWAV(pcm), 0644); err != nil {
+		return fmt.Errorf("failed to write wav file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+// Package cleanup tidies transcripts with deterministic rules, without an LLM.
+// It is a fast first pass, and the fallback when no LLM is available.
+package cleanup
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fillers are words dropped wherever they appear
+var fillers = map[string]bool{
+	"um":  true,
+	"umm": true,
+	"uh":  true,
+	"uhh": true,
+	"uhm": true,
+	"er":  true,
+	"erm": true,
+	"ah":  true,
+	"hmm": true,
+	"mm":  true,
+	"mhm": true,
+	"eh":  true,
+	"ehm": true,
+}
+
+// fillerPhrases are only dropped when set off by commas or the start or end of
+// a sentence, as in "it was, you know, fine", so "do you know" is kept
+var fillerPhrases = [][]string{
+	{"you", "know"},
+	{"i", "mean"},
+	{"kind", "of", "like"},
+}
+
+// allowedRepeats are words that are commonly repeated on purpose, as in "I said that that was fine"
+var allowedRepeats = map[string]bool{
+	"that": true,
+	"had":  true,
+	"is":   true,
+	"very": true,
+	"no":   true,
+	"bye":  true,
+}
+
+// capitalised are words that are always capitalised, keyed by their lower case form
+var capitalised = map[string]string{
+	"i":    "I",
+	"i'm":  "I'm",
+	"i've": "I've",
+	"i'll": "I'll",
+	"i'd":  "I'd",
+}
+
+// Clean removes fillers, stutters and repeated words, and fixes spacing and
+// capitalisation. Line breaks, such as those inserted at the end of each
+// Deepgram utterance, are kept with each line made a sentence.
+func Clean(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		words := tokenize(line)
+		words = removeFillerPhrases(words)
+		words = removeFillers(words)
+		words = collapseStutters(words)
+		words = collapseRepeats(words)
+		words = trimDashes(words)
+		if len(words) == 0 {
+			continue
+		}
+		lines = append(lines, sentence(words))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tokenize splits a line into words, attaching stray punctuation to the previous word
+func tokenize(line string) []string {
+	var words []string
+	for _, field := range strings.Fields(line) {
+		// Dashes between words are kept as words, so they are not mistaken for a cut off word
+		if isPunctuation(field) && !isDash(field) && len(words) > 0 {
+			words[len(words)-1] += field
+			continue
+		}
+		words = append(words, field)
+	}
+	return words
+}
+
+func isDash(s string) bool {
+	return strings.Trim(s, "-–—") == ""
+}
+
+func isPunctuation(s string) bool {
+	return strings.TrimFunc(s, unicode.IsPunct) == ""
+}
+
+// normalize lower cases a word and strips surrounding punctuation for comparison
+func normalize(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r) && r != '\''
+	}))
+}
+
+// trailing returns the punctuation at the end of a word
+func trailing(word string) string {
+	trimmed := strings.TrimRightFunc(word, unicode.IsPunct)
+	return word[len(trimmed):]
+}
+
+func endsSentence(word string) bool {
+	return strings.ContainsAny(trailing(word), ".!?")
+}
+
+// removeFillers drops filler words
+func removeFillers(words []string) []string {
+	out := make([]string, 0, len(words))
+	for _, word := range words {
+		if !fillers[normalize(word)] {
+			out = append(out, word)
+			continue
+		}
+		mergePunctuation(out, word)
+	}
+	return out
+}
+
+// mergePunctuation keeps the punctuation of a removed word correct on the word
+// before it. A sentence end moves back, and commas setting off the removed
+// word are dropped, so "it was, um, fine" becomes "it was fine".
+func mergePunctuation(out []string, removed string) {
+	if len(out) == 0 {
+		return
+	}
+	prev := out[len(out)-1]
+	switch {
+	case endsSentence(removed):
+		out[len(out)-1] = strings.TrimRight(prev, ",;:") + strings.TrimLeft(trailing(removed), ",;:")
+	case trailing(removed) == "," && strings.HasSuffix(prev, ","):
+		out[len(out)-1] = strings.TrimSuffix(prev, ",")
+	}
+}
+
+// removeFillerPhrases drops filler phrases that are set off from the rest of the sentence
+func removeFillerPhrases(words []string) []string {
+	out := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		phrase := matchPhrase(words, i)
+		if phrase == 0 {
+			out = append(out, words[i])
+			continue
+		}
+
+		last := words[i+phrase-1]
+		startsClause := len(out) == 0 || strings.ContainsAny(trailing(out[len(out)-1]), ",.!?;:")
+		endsClause := i+phrase == len(words) || trailing(last) != ""
+		if !startsClause || !endsClause {
+			out = append(out, words[i])
+			continue
+		}
+
+		mergePunctuation(out, last)
+		i += phrase - 1
+	}
+	return out
+}
+
+// matchPhrase returns the length of the filler phrase starting at words[i], or 0 if there is none
+func matchPhrase(words []string, i int) int {
+	for _, phrase := range fillerPhrases {
+		if i+len(phrase) > len(words) {
+			continue
+		}
+		matched := true
+		for j, want := range phrase {
+			word := words[i+j]
+			// Only the last word of the phrase may carry punctuation
+			if normalize(word) != want || (j < len(phrase)-1 && trailing(word) != "") {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return len(phrase)
+		}
+	}
+	return 0
+}
+
+// collapseStutters reduces "th-th-the" to "the", and drops a cut off "I-" before "I"
+func collapseStutters(words []string) []string {
+	out := make([]string, 0, len(words))
+	for i, word := range words {
+		// A word cut off with a dash, repeated in full by the next word
+		if stem := strings.TrimRight(word, "-—"); stem != word && stem != "" && i+1 < len(words) {
+			if strings.HasPrefix(normalize(words[i+1]), strings.ToLower(stem)) {
+				continue
+			}
+		}
+		out = append(out, collapseHyphenStutter(word))
+	}
+	return out
+}
+
+// hyphenatedRepeats are words that are repeated with a hyphen on purpose, as in "so-so"
+var hyphenatedRepeats = map[string]bool{
+	"so":    true,
+	"bye":   true,
+	"no":    true,
+	"night": true,
+	"hush":  true,
+	"goody": true,
+	"tut":   true,
+	"chop":  true,
+	"knock": true,
+}
+
+// collapseHyphenStutter reduces a hyphenated stutter to its last part. Only a
+// full repeat of the word, as in "I-I-I", or cut off parts that the word
+// repeats in full, as in "th-the" or "t-t-test", count as a stutter. Hyphenated
+// words such as "well-known", "t-test", "D-Day" or "A-A" are kept.
+func collapseHyphenStutter(word string) string {
+	parts := strings.Split(word, "-")
+	if len(parts) < 2 {
+		return word
+	}
+
+	last := parts[len(parts)-1]
+	lastNorm := normalize(last)
+	if lastNorm == "" {
+		return word
+	}
+	leading := parts[:len(parts)-1]
+	fullRepeat := true
+	for _, part := range leading {
+		part = strings.ToLower(part)
+		if part == "" || !strings.HasPrefix(lastNorm, part) {
+			return word
+		}
+		fullRepeat = fullRepeat && part == lastNorm
+	}
+
+	first, _ := utf8.DecodeRuneInString(parts[0])
+	lastFirst, _ := utf8.DecodeRuneInString(last)
+	switch {
+	case fullRepeat:
+		// A repeated single letter is an abbreviation such as "A-A", except for "I-I"
+		if (utf8.RuneCountInString(lastNorm) == 1 && lastNorm != "i") || hyphenatedRepeats[lastNorm] {
+			return word
+		}
+	case unicode.IsUpper(lastFirst) && utf8.RuneCountInString(parts[0]) == 1:
+		// A capitalised compound such as "D-Day" or "X-Ray"
+		return word
+	case len(leading) == 1 && utf8.RuneCountInString(lastNorm)-utf8.RuneCountInString(leading[0]) > 1:
+		// A single cut off part must stop one letter short, so "t-test" is kept
+		return word
+	}
+
+	// Keep the case of the stutter, so "Th-the" stays capitalised
+	if unicode.IsUpper(first) {
+		return capitalize(last)
+	}
+	return last
+}
+
+// trimDashes drops dashes left at the start or end of a line once words around them are removed
+func trimDashes(words []string) []string {
+	for len(words) > 0 && isDash(words[0]) {
+		words = words[1:]
+	}
+	for len(words) > 0 && isDash(words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+	return words
+}
+
+// collapseRepeats drops immediately repeated words and word pairs, as in "the the" or "I think I think"
+func collapseRepeats(words []string) []string {
+	out := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		next := i + 1
+		if next < len(words) && repeats(word, words[next]) && !allowedRepeats[normalize(word)] {
+			continue
+		}
+		if i+3 < len(words) && repeats(words[i+1], words[i+3]) && repeats(word, words[i+2]) && trailing(words[i]) == "" {
+			i++
+			continue
+		}
+		out = append(out, word)
+	}
+	return out
+}
+
+// repeats reports whether b repeats a, allowing a comma between them as in "I, I think"
+func repeats(a, b string) bool {
+	punct := trailing(a)
+	if punct != "" && punct != "," {
+		return false
+	}
+	na := normalize(a)
+	return na != "" && na == normalize(b)
+}
+
+// sentence joins words into a line, capitalising each sentence and ending it with punctuation
+func sentence(words []string) string {
+	capitalizeNext := true
+	for i, word := range words {
+		if fixed, ok := capitalised[normalize(word)]; ok {
+			word = strings.Replace(word, normalize(word), fixed, 1)
+		}
+		if capitalizeNext {
+			word = capitalize(word)
+		}
+		capitalizeNext = endsSentence(word)
+		words[i] = word
+	}
+
+	last := len(words) - 1
+	if !endsSentence(words[last]) {
+		words[last] = strings.TrimRight(words[last], ",;:-") + "."
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize upper cases the first letter of a word, skipping leading punctuation such as quotes
+func capitalize(word string) string {
+	for i, r := range word {
+		if unicode.IsLetter(r) {
+			return word[:i] + string(unicode.ToUpper(r)) + word[i+utf8.RuneLen(r):]
+		}
+		if !unicode.IsPunct(r) {
+			return word
+		}
+	}
+	return word
+}
@@ -0,0 +1,78 @@
+package cleanup
+
+import "testing"
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"capitalises and ends sentence", "this is fine", "This is fine."},
+		{"fillers", "um so it was uh fine", "So it was fine."},
+		{"filler set off by commas", "it was, um, fine", "It was fine."},
+		{"filler ending sentence", "that was it um. next one", "That was it. Next one."},
+		{"filler phrase set off", "it was, you know, fine", "It was fine."},
+		{"filler phrase in sentence kept", "do you know the way", "Do you know the way."},
+		{"repeated word", "the the cat sat", "The cat sat."},
+		{"repeated word with comma", "I, I think so", "I think so."},
+		{"allowed repeat", "I said that that was fine", "I said that that was fine."},
+		{"repeated pair", "I think I think it works", "I think it works."},
+		{"cut off word", "th- the cat", "The cat."},
+		{"cut off I", "I- I want it", "I want it."},
+		{"hyphen stutter", "th-the cat", "The cat."},
+		{"repeated hyphen stutter", "it was t-t-terrible", "It was terrible."},
+		{"full hyphen repeat", "I-I-I don't know", "I don't know."},
+		{"full hyphen repeat of word", "put it on the-the table", "Put it on the table."},
+		{"capitalised stutter", "Th-the end", "The end."},
+		{"hyphenated word", "a well-known fact", "A well-known fact."},
+		{"prefixed word", "please re-read it", "Please re-read it."},
+		{"capitalised compound", "D-Day was on the sixth", "D-Day was on the sixth."},
+		{"single letter compound", "the t-test results", "The t-test results."},
+		{"abbreviation", "an A-A battery", "An A-A battery."},
+		{"reduplicated word", "it was so-so", "It was so-so."},
+		{"lone dash", "-", ""},
+		{"trailing dash", "and then -", "And then."},
+		{"dash between words kept", "it was - fine", "It was - fine."},
+		{"lines kept", "first line\nsecond line", "First line.\nSecond line."},
+		{"empty lines dropped", "um\nsecond line", "Second line."},
+		{"i capitalised", "i think i'm right", "I think I'm right."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Clean(tt.in); got != tt.want {
+				t.Errorf("Clean(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollapseHyphenStutter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"th-the", "the"},
+		{"Th-the", "The"},
+		{"w-we", "we"},
+		{"I-I", "I"},
+		{"the-the", "the"},
+		{"t-t-test", "test"},
+		{"t-test", "t-test"},
+		{"D-Day", "D-Day"},
+		{"X-Ray", "X-Ray"},
+		{"A-A", "A-A"},
+		{"so-so", "so-so"},
+		{"well-known", "well-known"},
+		{"-", "-"},
+		{"word", "word"},
+	}
+
+	for _, tt := range tests {
+		if got := collapseHyphenStutter(tt.in); got != tt.want {
+			t.Errorf("collapseHyphenStutter(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
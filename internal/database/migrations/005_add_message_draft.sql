@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN draft INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+CREATE TABLE vocabulary
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    term       TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_vocabulary_term ON vocabulary (term COLLATE NOCASE);
@@ -0,0 +1,12 @@
+CREATE TABLE message_words
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_id INTEGER NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    word       TEXT    NOT NULL,
+    start_secs REAL    NOT NULL,
+    end_secs   REAL    NOT NULL,
+    confidence REAL    NOT NULL
+);
+
+CREATE INDEX idx_message_words_message ON message_words (message_id, position);
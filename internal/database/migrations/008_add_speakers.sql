@@ -0,0 +1,11 @@
+ALTER TABLE message_words ADD COLUMN speaker INTEGER;
+
+CREATE TABLE thread_speakers
+(
+    thread_id  INTEGER NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    speaker    INTEGER NOT NULL,
+    name       TEXT    NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (thread_id, speaker)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE prompt_templates
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    builtin_key TEXT,
+    name        TEXT NOT NULL,
+    prompt      TEXT NOT NULL,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_prompt_templates_builtin_key ON prompt_templates (builtin_key);
@@ -0,0 +1,25 @@
+CREATE TABLE message_revisions
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_id    INTEGER NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    text          TEXT    NOT NULL,
+    source        TEXT    NOT NULL,
+    prompt        TEXT,
+    model         TEXT,
+    template_name TEXT,
+    restored_from INTEGER REFERENCES message_revisions (id) ON DELETE SET NULL,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_message_revisions_message ON message_revisions (message_id);
+
+-- Existing messages start their history with the transcript, followed by any improved text
+INSERT INTO message_revisions (message_id, text, source, created_at)
+SELECT id, original_text, 'transcript', created_at
+FROM messages;
+
+INSERT INTO message_revisions (message_id, text, source, created_at)
+SELECT id, text, 'llm', updated_at
+FROM messages
+WHERE text IS NOT NULL
+  AND text != '';
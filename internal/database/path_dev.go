@@ -2,9 +2,22 @@
 
 package database
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
 
 func GetDatabasePath() (string, error) {
 	slog.Info("development mode: using local database")
 	return "dictation_dev.db", nil
 }
+
+// GetDataDirectory returns the directory used for files stored alongside the database
+func GetDataDirectory() (string, error) {
+	dataDir := "dictation_dev_data"
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return dataDir, nil
+}
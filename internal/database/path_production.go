@@ -12,6 +12,16 @@ import (
 func GetDatabasePath() (string, error) {
 	slog.Info("production mode: using user data directory")
 
+	dataDir, err := GetDataDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dataDir, "dictation.db"), nil
+}
+
+// GetDataDirectory returns the directory used for files stored alongside the database
+func GetDataDirectory() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
@@ -22,5 +32,5 @@ func GetDatabasePath() (string, error) {
 		return "", fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	return filepath.Join(dataDir, "dictation.db"), nil
+	return dataDir, nil
 }
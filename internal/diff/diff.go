@@ -0,0 +1,165 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+)
+
+// OpType is the kind of change an Op makes
+type OpType string
+
+const (
+	Equal  OpType = "equal"
+	Insert OpType = "insert"
+	Delete OpType = "delete"
+)
+
+// Op is a run of words that are unchanged, inserted or deleted
+type Op struct {
+	Type OpType `json:"type"`
+	Text string `json:"text"`
+}
+
+// maxCells caps the work of diffing the changed middle of two texts. Beyond
+// it the middle is reported as deleted and inserted whole, so comparing two
+// very long dictations stays fast.
+const maxCells = 100_000_000
+
+// Words diffs two texts word by word, using the longest common subsequence.
+// Consecutive words with the same OpType are merged into a single Op.
+//
+// The common prefix and suffix are trimmed first, and the rest is diffed with
+// Hirschberg's algorithm, which needs memory linear in the length of the texts.
+func Words(from, to string) []Op {
+	a, b := strings.Fields(from), strings.Fields(to)
+
+	var ops []Op
+	add := func(opType OpType, word string) {
+		if n := len(ops); n > 0 && ops[n-1].Type == opType {
+			ops[n-1].Text += " " + word
+			return
+		}
+		ops = append(ops, Op{Type: opType, Text: word})
+	}
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, word := range a[:prefix] {
+		add(Equal, word)
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxCells {
+		for _, word := range midA {
+			add(Delete, word)
+		}
+		for _, word := range midB {
+			add(Insert, word)
+		}
+	} else {
+		// Words are compared as ids, which is much faster than comparing strings
+		ids := make(map[string]int)
+		toIDs := func(words []string) []int {
+			out := make([]int, len(words))
+			for i, word := range words {
+				id, ok := ids[word]
+				if !ok {
+					id = len(ids)
+					ids[word] = id
+				}
+				out[i] = id
+			}
+			return out
+		}
+		hirschberg(midA, midB, toIDs(midA), toIDs(midB), add)
+	}
+
+	for _, word := range a[len(a)-suffix:] {
+		add(Equal, word)
+	}
+	return ops
+}
+
+// hirschberg emits the ops turning a into b, splitting a in half and finding
+// where the longest common subsequence crosses the split in b. idA and idB
+// hold the id of each word of a and b.
+func hirschberg(a, b []string, idA, idB []int, add func(OpType, string)) {
+	switch {
+	case len(a) == 0:
+		for _, word := range b {
+			add(Insert, word)
+		}
+		return
+	case len(b) == 0:
+		for _, word := range a {
+			add(Delete, word)
+		}
+		return
+	case len(a) == 1:
+		at := slices.Index(idB, idA[0])
+		if at < 0 {
+			add(Delete, a[0])
+			for _, word := range b {
+				add(Insert, word)
+			}
+			return
+		}
+		for _, word := range b[:at] {
+			add(Insert, word)
+		}
+		add(Equal, a[0])
+		for _, word := range b[at+1:] {
+			add(Insert, word)
+		}
+		return
+	}
+
+	mid := len(a) / 2
+	forward := lcsLengths(idA[:mid], idB, false)
+	backward := lcsLengths(idA[mid:], idB, true)
+
+	// Split b where the common subsequences of both halves are longest together
+	split, best := 0, -1
+	for k := 0; k <= len(b); k++ {
+		if n := forward[k] + backward[len(b)-k]; n > best {
+			split, best = k, n
+		}
+	}
+
+	hirschberg(a[:mid], b[:split], idA[:mid], idB[:split], add)
+	hirschberg(a[mid:], b[split:], idA[mid:], idB[split:], add)
+}
+
+// lcsLengths returns, for each k, the length of the longest common subsequence
+// of a and the first k words of b. With reverse set, both are read backwards,
+// so k counts words from the end of b.
+func lcsLengths(a, b []int, reverse bool) []int {
+	at := func(words []int, i int) int {
+		if reverse {
+			return words[len(words)-1-i]
+		}
+		return words[i]
+	}
+
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		word := at(a, i)
+		for j := 1; j <= len(b); j++ {
+			if word == at(b, j-1) {
+				cur[j] = prev[j-1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
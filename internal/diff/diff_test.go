@@ -0,0 +1,122 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []Op
+	}{
+		{"same", "a b c", "a b c", []Op{{Equal, "a b c"}}},
+		{"empty", "", "", nil},
+		{"all inserted", "", "a b", []Op{{Insert, "a b"}}},
+		{"all deleted", "a b", "", []Op{{Delete, "a b"}}},
+		{"replace middle", "a b c", "a x c", []Op{{Equal, "a"}, {Delete, "b"}, {Insert, "x"}, {Equal, "c"}}},
+		{"insert", "the cat sat", "the black cat sat", []Op{{Equal, "the"}, {Insert, "black"}, {Equal, "cat sat"}}},
+		{"delete", "um so the plan", "so the plan", []Op{{Delete, "um"}, {Equal, "so the plan"}}},
+		{
+			"moved word",
+			"one two three four",
+			"two three one four",
+			[]Op{{Delete, "one"}, {Equal, "two three"}, {Insert, "one"}, {Equal, "four"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Words(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWordsRandom checks the ops rebuild both texts and keep a longest common
+// subsequence, against a plain dynamic programming table
+func TestWordsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := []string{"a", "b", "c", "d"}
+	text := func() []string {
+		out := make([]string, rng.Intn(12))
+		for i := range out {
+			out[i] = words[rng.Intn(len(words))]
+		}
+		return out
+	}
+
+	for range 500 {
+		a, b := text(), text()
+		ops := Words(strings.Join(a, " "), strings.Join(b, " "))
+
+		var from, to []string
+		equal := 0
+		for _, op := range ops {
+			opWords := strings.Fields(op.Text)
+			switch op.Type {
+			case Equal:
+				from, to = append(from, opWords...), append(to, opWords...)
+				equal += len(opWords)
+			case Delete:
+				from = append(from, opWords...)
+			case Insert:
+				to = append(to, opWords...)
+			}
+		}
+		if strings.Join(from, " ") != strings.Join(a, " ") || strings.Join(to, " ") != strings.Join(b, " ") {
+			t.Fatalf("ops %v do not rebuild %v and %v", ops, a, b)
+		}
+		if want := lcsTable(a, b); equal != want {
+			t.Fatalf("ops %v keep %d words of %v and %v, want %d", ops, equal, a, b, want)
+		}
+	}
+}
+
+func lcsTable(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
+
+func TestWordsLongTexts(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	text := func(n int) string {
+		words := make([]string, n)
+		for i := range words {
+			words[i] = string(rune('a' + rng.Intn(26)))
+		}
+		return strings.Join(words, " ")
+	}
+
+	start := time.Now()
+	ops := Words(text(12_000), text(12_000))
+	if len(ops) != 2 || ops[0].Type != Delete || ops[1].Type != Insert {
+		t.Fatalf("got %d ops, want the changed middle replaced whole", len(ops))
+	}
+
+	shared := text(6_000)
+	ops = Words("intro "+shared+" end", "opening "+shared+" ending")
+	if len(ops) != 5 || ops[2].Type != Equal {
+		t.Fatalf("got %d ops, want the shared words kept", len(ops))
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("diffing long texts took %s", elapsed)
+	}
+}
@@ -0,0 +1,369 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mac-dictation/internal/audio"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	metaFile     = "meta.json"
+	audioFile    = "audio.pcm"
+	segmentsFile = "segments.jsonl"
+
+	// maxPendingBytes bounds the audio waiting to be written if the disk stalls
+	maxPendingBytes = 10 * audio.BytesPerSecond
+)
+
+// Meta describes the recording a journal belongs to
+type Meta struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	// ThreadID is the thread the recording would have been added to, nil for a new thread
+	ThreadID *int   `json:"threadId"`
+	Provider string `json:"provider"`
+}
+
+// Segment is a final transcript received while recording. EndSecs is how far
+// into the journal's audio the text covers.
+type Segment struct {
+	Text    string  `json:"text"`
+	EndSecs float64 `json:"endSecs"`
+}
+
+// Journal persists a recording's PCM audio and final transcript segments to
+// disk as they arrive, so a recording can be recovered if the app crashes.
+//
+// Each journal is a directory holding meta.json, the raw audio and one JSON
+// segment per line. Journals are removed once the recording is saved as a message.
+//
+// Write is called from the audio callback, so it only queues audio in memory.
+// A writer goroutine moves it to disk.
+type Journal struct {
+	dir string
+	id  string
+
+	// pendingMu guards audio waiting to be written, and is never held during disk I/O.
+	// discard drops audio once the journal is closed or the disk has fallen too far behind.
+	pendingMu sync.Mutex
+	pending   []byte
+	discard   bool
+	wake      chan struct{}
+	stop      chan struct{}
+	stopped   chan struct{}
+	stopOnce  sync.Once
+
+	mu       sync.Mutex
+	audio    *os.File
+	writer   *bufio.Writer
+	segments *os.File
+	// spare is the pending buffer last written, reused to avoid allocating
+	spare  []byte
+	closed bool
+	// failed stops writes after the first error so a full disk only logs once
+	failed bool
+}
+
+// Create starts a new journal under root
+func Create(root string, meta Meta) (*Journal, error) {
+	if meta.ID == "" {
+		meta.ID = meta.StartedAt.UTC().Format("20060102_150405.000000")
+	}
+
+	dir := filepath.Join(root, meta.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode journal meta: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, metaFile), metaData, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write journal meta: %w", err)
+	}
+
+	audioF, err := os.OpenFile(filepath.Join(dir, audioFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal audio: %w", err)
+	}
+
+	segmentsF, err := os.OpenFile(filepath.Join(dir, segmentsFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		_ = audioF.Close()
+		return nil, fmt.Errorf("failed to create journal segments: %w", err)
+	}
+
+	// At most a second of written audio is buffered before reaching disk
+	j := &Journal{
+		dir:      dir,
+		id:       meta.ID,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		audio:    audioF,
+		writer:   bufio.NewWriterSize(audioF, audio.BytesPerSecond),
+		segments: segmentsF,
+	}
+	go j.run()
+	return j, nil
+}
+
+func (j *Journal) ID() string {
+	return j.id
+}
+
+// Write queues PCM audio to be appended without blocking on disk. Errors are
+// logged rather than returned so a failing journal never interrupts the recording.
+func (j *Journal) Write(pcm []byte) (int, error) {
+	j.pendingMu.Lock()
+	if j.discard {
+		j.pendingMu.Unlock()
+		return len(pcm), nil
+	}
+	if len(j.pending)+len(pcm) > maxPendingBytes {
+		j.discard = true
+		j.pending = nil
+		j.pendingMu.Unlock()
+		slog.Error("journal audio is not reaching disk, journaling disabled", "journal", j.id)
+		return len(pcm), nil
+	}
+	j.pending = append(j.pending, pcm...)
+	j.pendingMu.Unlock()
+
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+	return len(pcm), nil
+}
+
+// run writes queued audio to disk until the journal is closed
+func (j *Journal) run() {
+	defer close(j.stopped)
+
+	for {
+		select {
+		case <-j.wake:
+			j.mu.Lock()
+			j.writePending()
+			j.mu.Unlock()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+// writePending moves queued audio into the file's buffer. Must be called with mu held.
+func (j *Journal) writePending() {
+	j.pendingMu.Lock()
+	pcm := j.pending
+	j.pending = j.spare[:0]
+	j.pendingMu.Unlock()
+	j.spare = pcm
+
+	if len(pcm) == 0 || j.closed || j.failed {
+		return
+	}
+	if _, err := j.writer.Write(pcm); err != nil {
+		j.failed = true
+		slog.Error("failed to write journal audio, journaling disabled", "error", err, "journal", j.id)
+	}
+}
+
+// AppendSegment records a final transcript segment, flushing the audio it covers first
+func (j *Journal) AppendSegment(segment Segment) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return fmt.Errorf("journal closed")
+	}
+
+	j.writePending()
+	if err := j.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush journal audio: %w", err)
+	}
+
+	line, err := json.Marshal(segment)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal segment: %w", err)
+	}
+	if _, err := j.segments.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal segment: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the journal, leaving it on disk
+func (j *Journal) Close() error {
+	j.stopOnce.Do(func() {
+		close(j.stop)
+		<-j.stopped
+	})
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return nil
+	}
+	j.writePending()
+	j.closed = true
+
+	j.pendingMu.Lock()
+	j.discard = true
+	j.pendingMu.Unlock()
+
+	return errors.Join(j.writer.Flush(), j.audio.Close(), j.segments.Close())
+}
+
+// Remove closes the journal and deletes it from disk
+func (j *Journal) Remove() error {
+	if err := j.Close(); err != nil {
+		slog.Warn("failed to close journal before removing", "error", err, "journal", j.id)
+	}
+	return os.RemoveAll(j.dir)
+}
+
+// Entry is a journal left on disk, typically by a recording that never finished
+type Entry struct {
+	Meta
+	DurationSecs float64   `json:"durationSecs"`
+	Segments     []Segment `json:"segments"`
+}
+
+// Text joins the final transcript segments
+func (e Entry) Text() string {
+	var text string
+	for _, segment := range e.Segments {
+		if text != "" {
+			text += " "
+		}
+		text += segment.Text
+	}
+	return text
+}
+
+// TranscribedSecs is how far into the audio the final segments reach
+func (e Entry) TranscribedSecs() float64 {
+	var end float64
+	for _, segment := range e.Segments {
+		end = max(end, segment.EndSecs)
+	}
+	return end
+}
+
+// List returns the journals under root, oldest first. Unreadable journals are skipped.
+func List(root string) ([]Entry, error) {
+	dirs, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	var entries []Entry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		entry, err := Load(root, dir.Name())
+		if err != nil {
+			slog.Warn("skipping unreadable journal", "error", err, "journal", dir.Name())
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return entries, nil
+}
+
+// Load reads a journal's metadata and segments
+func Load(root, id string) (*Entry, error) {
+	dir, err := journalDir(root, id)
+	if err != nil {
+		return nil, err
+	}
+
+	metaData, err := os.ReadFile(filepath.Join(dir, metaFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal meta: %w", err)
+	}
+
+	entry := &Entry{}
+	if err := json.Unmarshal(metaData, &entry.Meta); err != nil {
+		return nil, fmt.Errorf("failed to parse journal meta: %w", err)
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, audioFile)); err == nil {
+		entry.DurationSecs = float64(info.Size()) / audio.BytesPerSecond
+	}
+
+	segmentsF, err := os.Open(filepath.Join(dir, segmentsFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open journal segments: %w", err)
+	}
+	if segmentsF != nil {
+		defer segmentsF.Close()
+
+		scanner := bufio.NewScanner(segmentsF)
+		for scanner.Scan() {
+			var segment Segment
+			// A crash can leave a partially written last line
+			if err := json.Unmarshal(scanner.Bytes(), &segment); err != nil {
+				slog.Warn("skipping malformed journal segment", "error", err, "journal", id)
+				continue
+			}
+			entry.Segments = append(entry.Segments, segment)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read journal segments: %w", err)
+		}
+	}
+
+	return entry, nil
+}
+
+// ReadAudio returns a journal's PCM audio, trimmed to whole samples
+func ReadAudio(root, id string) ([]byte, error) {
+	dir, err := journalDir(root, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pcm, err := os.ReadFile(filepath.Join(dir, audioFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal audio: %w", err)
+	}
+	return pcm[:len(pcm)-len(pcm)%audio.BytesPerSample], nil
+}
+
+// Discard deletes a journal from disk
+func Discard(root, id string) error {
+	dir, err := journalDir(root, id)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// journalDir resolves a journal ID, rejecting IDs that would escape root
+func journalDir(root, id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid journal id %q", id)
+	}
+	return filepath.Join(root, id), nil
+}
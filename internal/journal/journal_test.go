@@ -0,0 +1,93 @@
+package journal
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJournalRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	threadID := 7
+	j, err := Create(root, Meta{StartedAt: time.Now(), ThreadID: &threadID, Provider: "deepgram"})
+	if err != nil {
+		t.Fatalf("failed to create journal: %v", err)
+	}
+
+	var want []byte
+	for i := range 50 {
+		chunk := bytes.Repeat([]byte{byte(i)}, 320)
+		want = append(want, chunk...)
+		if _, err := j.Write(chunk); err != nil {
+			t.Fatalf("failed to write audio: %v", err)
+		}
+		if i == 24 {
+			if err := j.AppendSegment(Segment{Text: "first", EndSecs: 0.25}); err != nil {
+				t.Fatalf("failed to append segment: %v", err)
+			}
+		}
+	}
+	if err := j.AppendSegment(Segment{Text: "second", EndSecs: 0.5}); err != nil {
+		t.Fatalf("failed to append segment: %v", err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatalf("failed to close journal: %v", err)
+	}
+
+	entry, err := Load(root, j.ID())
+	if err != nil {
+		t.Fatalf("failed to load journal: %v", err)
+	}
+	if entry.Text() != "first second" {
+		t.Errorf("text %q, want %q", entry.Text(), "first second")
+	}
+	if entry.ThreadID == nil || *entry.ThreadID != threadID {
+		t.Errorf("thread %v, want %d", entry.ThreadID, threadID)
+	}
+
+	pcm, err := ReadAudio(root, j.ID())
+	if err != nil {
+		t.Fatalf("failed to read audio: %v", err)
+	}
+	if !bytes.Equal(pcm, want) {
+		t.Errorf("read %d bytes of audio, want %d", len(pcm), len(want))
+	}
+}
+
+// TestJournalConcurrentWrites writes audio from one goroutine, as the audio
+// callback does, while segments are appended from another
+func TestJournalConcurrentWrites(t *testing.T) {
+	root := t.TempDir()
+	j, err := Create(root, Meta{StartedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("failed to create journal: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 500 {
+			_, _ = j.Write(make([]byte, 320))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 20 {
+			_ = j.AppendSegment(Segment{Text: "segment"})
+		}
+	}()
+	wg.Wait()
+
+	if err := j.Remove(); err != nil {
+		t.Fatalf("failed to remove journal: %v", err)
+	}
+	// Audio arriving after the journal is closed is dropped
+	if _, err := j.Write(make([]byte, 320)); err != nil {
+		t.Fatalf("write after close: %v", err)
+	}
+	if entries, _ := List(root); len(entries) != 0 {
+		t.Fatalf("%d journals left after remove, want 0", len(entries))
+	}
+}
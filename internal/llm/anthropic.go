@@ -0,0 +1,205 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	Claude35Haiku = "claude-3-5-haiku-latest"
+
+	anthropicURL     = "https://api.anthropic.com/v1/messages"
+	anthropicVersion = "2023-06-01"
+	// anthropicMaxTokens bounds the response, the API requires a limit on every request
+	anthropicMaxTokens = 4096
+)
+
+// AnthropicService generates text with the Anthropic Messages API
+type AnthropicService struct {
+	apiKey string
+	model  string
+}
+
+var _ LLM = &AnthropicService{}
+
+func NewAnthropicService(apiKey, model string) *AnthropicService {
+	if model == "" {
+		model = Claude35Haiku
+	}
+	return &AnthropicService{apiKey: apiKey, model: model}
+}
+
+// IsConfigured reports whether an API key has been provided
+func (s *AnthropicService) IsConfigured() bool {
+	return s.apiKey != ""
+}
+
+func (s *AnthropicService) Model() string {
+	return s.model
+}
+
+type AnthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type AnthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []AnthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float32            `json:"temperature"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type AnthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+}
+
+func (s *AnthropicService) request(systemPrompt, userPrompt string, stream bool) AnthropicRequest {
+	return AnthropicRequest{
+		Model:       s.model,
+		System:      systemPrompt,
+		Messages:    []AnthropicMessage{{Role: "user", Content: userPrompt}},
+		MaxTokens:   anthropicMaxTokens,
+		Temperature: 0.3,
+		Stream:      stream,
+	}
+}
+
+func (s *AnthropicService) Prompt(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	requestBody := s.request(systemPrompt, userPrompt, false)
+
+	slog.Info("Sending Anthropic request", "model", s.model)
+	res, err := s.send(ctx, requestBody)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(res.Body)
+
+	var response AnthropicResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+// Messages API streaming event types
+//
+// https://docs.anthropic.com/en/api/messages-streaming
+const (
+	anthropicContentBlockDelta = "content_block_delta"
+	anthropicMessageStop       = "message_stop"
+	anthropicError             = "error"
+)
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// PromptStream is like Prompt but streams the response. Cancelling ctx aborts the request.
+func (s *AnthropicService) PromptStream(ctx context.Context, systemPrompt, userPrompt string, onDelta func(string)) (string, error) {
+	requestBody := s.request(systemPrompt, userPrompt, true)
+
+	slog.Info("Sending Anthropic streaming request", "model", s.model)
+	res, err := s.send(ctx, requestBody)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(res.Body)
+
+	var text strings.Builder
+	completed := false
+	err = ReadServerSentEvents(res.Body, func(sse ServerSentEvent) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(sse.Data), &event); err != nil {
+			slog.Warn("Failed to parse Anthropic stream event", "error", err, "event", sse.Event)
+			return nil
+		}
+
+		switch event.Type {
+		case anthropicContentBlockDelta:
+			if event.Delta.Type != "text_delta" {
+				return nil
+			}
+			text.WriteString(event.Delta.Text)
+			if onDelta != nil {
+				onDelta(event.Delta.Text)
+			}
+		case anthropicMessageStop:
+			completed = true
+		case anthropicError:
+			return fmt.Errorf("Anthropic stream error: %s", event.Error.Message)
+		}
+		return nil
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if !completed {
+		return "", fmt.Errorf("Anthropic stream ended before the message completed")
+	}
+
+	slog.Info("Anthropic streaming response received", "length", text.Len())
+	return text.String(), nil
+}
+
+// send posts a request to the Messages API, returning the response if it succeeded
+//
+// https://docs.anthropic.com/en/api/messages
+func (s *AnthropicService) send(ctx context.Context, req AnthropicRequest) (*http.Response, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicURL, bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", s.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	if req.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	res, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStatus("Anthropic", res); err != nil {
+		closeBody(res.Body)
+		return nil, err
+	}
+	return res, nil
+}
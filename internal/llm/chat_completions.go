@@ -0,0 +1,197 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultChatCompletionsBaseURL is where Ollama serves its OpenAI compatible API by default.
+	// LM Studio listens on http://127.0.0.1:1234/v1.
+	DefaultChatCompletionsBaseURL = "http://127.0.0.1:11434/v1"
+	DefaultChatCompletionsModel   = "llama3.2"
+
+	// chatCompletionsDone marks the end of a chat completions event stream
+	chatCompletionsDone = "[DONE]"
+)
+
+// ChatCompletionsService generates text with any server exposing an OpenAI
+// compatible /chat/completions endpoint, such as Ollama or LM Studio
+type ChatCompletionsService struct {
+	baseURL string
+	// apiKey is optional, local servers typically accept unauthenticated requests
+	apiKey string
+	model  string
+}
+
+var _ LLM = &ChatCompletionsService{}
+
+func NewChatCompletionsService(baseURL, apiKey, model string) *ChatCompletionsService {
+	if baseURL == "" {
+		baseURL = DefaultChatCompletionsBaseURL
+	}
+	if model == "" {
+		model = DefaultChatCompletionsModel
+	}
+	return &ChatCompletionsService{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, model: model}
+}
+
+// IsConfigured is always true, as no API key is required
+func (s *ChatCompletionsService) IsConfigured() bool {
+	return true
+}
+
+func (s *ChatCompletionsService) Model() string {
+	return s.model
+}
+
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ChatCompletionsRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	Temperature float32       `json:"temperature"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+type ChatCompletionsResponse struct {
+	Choices []struct {
+		Message      ChatMessage `json:"message"`
+		Delta        ChatMessage `json:"delta"`
+		FinishReason *string     `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (s *ChatCompletionsService) request(systemPrompt, userPrompt string, stream bool) ChatCompletionsRequest {
+	var messages []ChatMessage
+	if systemPrompt != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: systemPrompt})
+	}
+	messages = append(messages, ChatMessage{Role: "user", Content: userPrompt})
+
+	return ChatCompletionsRequest{
+		Model:       s.model,
+		Messages:    messages,
+		Temperature: 0.3,
+		Stream:      stream,
+	}
+}
+
+func (s *ChatCompletionsService) Prompt(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	requestBody := s.request(systemPrompt, userPrompt, false)
+
+	slog.Info("Sending chat completions request", "baseURL", s.baseURL, "model", s.model)
+	res, err := s.send(ctx, requestBody)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(res.Body)
+
+	var response ChatCompletionsResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", nil
+	}
+	return response.Choices[0].Message.Content, nil
+}
+
+// PromptStream is like Prompt but streams the response. Cancelling ctx aborts the request.
+func (s *ChatCompletionsService) PromptStream(ctx context.Context, systemPrompt, userPrompt string, onDelta func(string)) (string, error) {
+	requestBody := s.request(systemPrompt, userPrompt, true)
+
+	slog.Info("Sending chat completions streaming request", "baseURL", s.baseURL, "model", s.model)
+	res, err := s.send(ctx, requestBody)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(res.Body)
+
+	var text strings.Builder
+	completed := false
+	err = ReadServerSentEvents(res.Body, func(sse ServerSentEvent) error {
+		if sse.Data == chatCompletionsDone {
+			completed = true
+			return nil
+		}
+
+		var chunk ChatCompletionsResponse
+		if err := json.Unmarshal([]byte(sse.Data), &chunk); err != nil {
+			slog.Warn("Failed to parse chat completions chunk", "error", err)
+			return nil
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("chat completions stream error: %s", chunk.Error.Message)
+		}
+
+		for _, choice := range chunk.Choices {
+			if delta := choice.Delta.Content; delta != "" {
+				text.WriteString(delta)
+				if onDelta != nil {
+					onDelta(delta)
+				}
+			}
+			// Not every server sends [DONE], but all set a finish reason
+			if choice.FinishReason != nil {
+				completed = true
+			}
+		}
+		return nil
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if !completed {
+		return "", fmt.Errorf("chat completions stream ended before the response completed")
+	}
+
+	slog.Info("Chat completions streaming response received", "length", text.Len())
+	return text.String(), nil
+}
+
+// send posts a request to the chat completions endpoint, returning the response if it succeeded
+func (s *ChatCompletionsService) send(ctx context.Context, req ChatCompletionsRequest) (*http.Response, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/chat/completions", bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+	if req.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	res, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", s.baseURL, err)
+	}
+
+	if err := checkStatus("Chat completions", res); err != nil {
+		closeBody(res.Body)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	ProviderOpenAI           = "openai"
+	ProviderAnthropic        = "anthropic"
+	ProviderOpenAICompatible = "openai-compatible"
+)
+
+// Providers are the selectable LLM providers
+var Providers = []string{ProviderOpenAI, ProviderAnthropic, ProviderOpenAICompatible}
+
+// LLM generates text from a system prompt and user input, used for thread
+// titles and cleaning up transcripts
+type LLM interface {
+	// Prompt returns the full response once complete
+	Prompt(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+	// PromptStream calls onDelta with each chunk of text as it arrives, and
+	// returns the full response once complete
+	PromptStream(ctx context.Context, systemPrompt, userPrompt string, onDelta func(string)) (string, error)
+	// IsConfigured reports whether the provider has what it needs to make requests
+	IsConfigured() bool
+	// Model is the name of the model responses are generated with
+	Model() string
+}
+
+// Config holds the settings used to build an LLM. Empty fields use the provider's defaults.
+type Config struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+}
+
+// New builds the LLM for the named provider
+func New(provider string, config Config) (LLM, error) {
+	switch provider {
+	case ProviderOpenAI, "":
+		return NewOpenAiService(config.APIKey, config.Model), nil
+	case ProviderAnthropic:
+		return NewAnthropicService(config.APIKey, config.Model), nil
+	case ProviderOpenAICompatible:
+		return NewChatCompletionsService(config.BaseURL, config.APIKey, config.Model), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", provider)
+	}
+}
+
+// checkStatus returns an error holding the response body if the request failed
+func checkStatus(name string, res *http.Response) error {
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, _ := io.ReadAll(res.Body)
+	return fmt.Errorf("%s API error (status %d): %s", name, res.StatusCode, string(body))
+}
+
+func closeBody(body io.ReadCloser) {
+	if err := body.Close(); err != nil {
+		slog.Error("failed to close response body", "error", err)
+	}
+}
@@ -0,0 +1,246 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type OpenAiRole string
+
+const (
+	Gpt4oMini = "gpt-4o-mini"
+
+	Developer OpenAiRole = "developer"
+	User      OpenAiRole = "user"
+)
+
+// OpenAiService generates text with the OpenAI Responses API
+type OpenAiService struct {
+	apiKey string
+	model  string
+}
+
+var _ LLM = &OpenAiService{}
+
+func NewOpenAiService(apiKey, model string) *OpenAiService {
+	if model == "" {
+		model = Gpt4oMini
+	}
+	return &OpenAiService{apiKey: apiKey, model: model}
+}
+
+// IsConfigured reports whether an API key has been provided
+func (s *OpenAiService) IsConfigured() bool {
+	return s.apiKey != ""
+}
+
+func (s *OpenAiService) Model() string {
+	return s.model
+}
+
+type OpenAiRequest struct {
+	Model        string   `json:"model"`
+	Instructions string   `json:"instructions,omitempty"`
+	Input        string   `json:"input"`
+	Temperature  *float32 `json:"temperature,omitempty"`
+	Stream       bool     `json:"stream,omitempty"`
+}
+
+type OpenAiResponse struct {
+	Output []Output `json:"output"`
+}
+
+type Output struct {
+	Id      string          `json:"id"`
+	Type    string          `json:"type"`
+	Role    string          `json:"role"`
+	Content []OutputContent `json:"content"`
+}
+
+type OutputContent struct {
+	Type        string   `json:"type"`
+	Text        string   `json:"text"`
+	Annotations []string `json:"annotations"`
+}
+
+// text returns the first output text of the response. Reasoning models put
+// reasoning items before the message, so those are skipped.
+func (r *OpenAiResponse) text() string {
+	for _, output := range r.Output {
+		if output.Type != "message" {
+			continue
+		}
+		for _, content := range output.Content {
+			if content.Type == "output_text" {
+				return content.Text
+			}
+		}
+	}
+	return ""
+}
+
+// reasoningModelPrefixes are the model families that reject a temperature
+var reasoningModelPrefixes = []string{"o1", "o3", "o4", "gpt-5"}
+
+// temperature returns the sampling temperature for the model, nil for reasoning models
+func (s *OpenAiService) temperature() *float32 {
+	for _, prefix := range reasoningModelPrefixes {
+		if strings.HasPrefix(s.model, prefix) {
+			return nil
+		}
+	}
+	temperature := float32(0.3)
+	return &temperature
+}
+
+func (s *OpenAiService) Prompt(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	requestBody := OpenAiRequest{
+		Model:        s.model,
+		Instructions: systemPrompt,
+		Input:        userPrompt,
+		Temperature:  s.temperature(),
+	}
+
+	slog.Info("Sending OpenAI request", "request", requestBody)
+	openAiResponse, err := s.responses(ctx, requestBody)
+	if err != nil {
+		return "", err
+	}
+	return openAiResponse.text(), nil
+}
+
+// responses sends a request to the OpenAI responses API
+//
+// https://platform.openai.com/docs/api-reference/responses
+func (s *OpenAiService) responses(ctx context.Context, req OpenAiRequest) (*OpenAiResponse, error) {
+	res, err := s.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res.Body)
+
+	var openAiResponse OpenAiResponse
+	if err := json.NewDecoder(res.Body).Decode(&openAiResponse); err != nil {
+		return nil, err
+	}
+
+	slog.Info("OpenAI response received", "response", openAiResponse)
+
+	return &openAiResponse, nil
+}
+
+// send posts a request to the responses API, returning the response if it succeeded
+func (s *OpenAiService) send(ctx context.Context, req OpenAiRequest) (*http.Response, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	reqwest, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/responses", bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	reqwest.Header.Set("Content-Type", "application/json")
+	reqwest.Header.Set("Authorization", "Bearer "+s.apiKey)
+	if req.Stream {
+		reqwest.Header.Set("Accept", "text/event-stream")
+	}
+
+	client := &http.Client{}
+	res, err := client.Do(reqwest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStatus("OpenAI", res); err != nil {
+		closeBody(res.Body)
+		return nil, err
+	}
+	return res, nil
+}
+
+// Responses API streaming event types
+//
+// https://platform.openai.com/docs/api-reference/responses-streaming
+const (
+	responseOutputTextDelta = "response.output_text.delta"
+	responseCompleted       = "response.completed"
+	responseFailed          = "response.failed"
+	responseIncomplete      = "response.incomplete"
+	responseError           = "error"
+)
+
+type openAiStreamEvent struct {
+	Type     string          `json:"type"`
+	Delta    string          `json:"delta"`
+	Message  string          `json:"message"`
+	Response *OpenAiResponse `json:"response"`
+}
+
+// PromptStream is like Prompt but streams the response. Cancelling ctx aborts the request.
+func (s *OpenAiService) PromptStream(ctx context.Context, systemPrompt, userPrompt string, onDelta func(string)) (string, error) {
+	requestBody := OpenAiRequest{
+		Model:        s.model,
+		Instructions: systemPrompt,
+		Input:        userPrompt,
+		Temperature:  s.temperature(),
+		Stream:       true,
+	}
+
+	slog.Info("Sending OpenAI streaming request", "request", requestBody)
+	res, err := s.send(ctx, requestBody)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(res.Body)
+
+	var text strings.Builder
+	completed := false
+	err = ReadServerSentEvents(res.Body, func(sse ServerSentEvent) error {
+		var event openAiStreamEvent
+		if err := json.Unmarshal([]byte(sse.Data), &event); err != nil {
+			slog.Warn("Failed to parse OpenAI stream event", "error", err, "event", sse.Event)
+			return nil
+		}
+
+		switch event.Type {
+		case responseOutputTextDelta:
+			text.WriteString(event.Delta)
+			if onDelta != nil {
+				onDelta(event.Delta)
+			}
+		case responseCompleted:
+			completed = true
+			// Prefer the final output in case any deltas were missed
+			if event.Response != nil {
+				if final := event.Response.text(); final != "" {
+					text.Reset()
+					text.WriteString(final)
+				}
+			}
+		case responseFailed, responseIncomplete:
+			return fmt.Errorf("OpenAI response did not complete: %s", event.Type)
+		case responseError:
+			return fmt.Errorf("OpenAI stream error: %s", event.Message)
+		}
+		return nil
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if !completed {
+		return "", fmt.Errorf("OpenAI stream ended before the response completed")
+	}
+
+	slog.Info("OpenAI streaming response received", "length", text.Len())
+	return text.String(), nil
+}
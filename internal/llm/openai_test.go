@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenAiResponseText(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"empty", `{"output": []}`, ""},
+		{
+			"message",
+			`{"output": [{"type": "message", "content": [{"type": "output_text", "text": "hello"}]}]}`,
+			"hello",
+		},
+		{
+			"reasoning first",
+			`{"output": [
+				{"type": "reasoning", "content": []},
+				{"type": "message", "content": [{"type": "output_text", "text": "hello"}]}
+			]}`,
+			"hello",
+		},
+		{
+			"refusal before text",
+			`{"output": [{"type": "message", "content": [
+				{"type": "refusal", "text": ""},
+				{"type": "output_text", "text": "hello"}
+			]}]}`,
+			"hello",
+		},
+		{"message without content", `{"output": [{"type": "message"}]}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res OpenAiResponse
+			if err := json.Unmarshal([]byte(tt.body), &res); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if got := res.text(); got != tt.want {
+				t.Errorf("text() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAiTemperature(t *testing.T) {
+	for _, model := range []string{"o1", "o3-mini", "o4-mini", "gpt-5", "gpt-5-mini"} {
+		if temp := NewOpenAiService("", model).temperature(); temp != nil {
+			t.Errorf("%s: temperature %v, want none for a reasoning model", model, *temp)
+		}
+	}
+	for _, model := range []string{"", "gpt-4o-mini", "gpt-4.1"} {
+		if temp := NewOpenAiService("", model).temperature(); temp == nil {
+			t.Errorf("%q: no temperature, want one", model)
+		}
+	}
+}
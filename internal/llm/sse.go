@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ServerSentEvent is a single event read from a text/event-stream body
+type ServerSentEvent struct {
+	Event string
+	Data  string
+}
+
+// ReadServerSentEvents parses an event stream, calling handle for each event.
+// It stops at the end of the stream or when handle returns an error.
+//
+// https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+func ReadServerSentEvents(r io.Reader, handle func(ServerSentEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	// A single event can carry a whole response, so allow lines well beyond the default 64KB
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var event ServerSentEvent
+	var data []string
+	dispatch := func() error {
+		if len(data) == 0 {
+			event = ServerSentEvent{}
+			return nil
+		}
+		event.Data = strings.Join(data, "\n")
+		err := handle(event)
+		event, data = ServerSentEvent{}, nil
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := dispatch(); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+
+	// The stream may end without a trailing blank line
+	return dispatch()
+}
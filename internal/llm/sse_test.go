@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadServerSentEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []ServerSentEvent
+	}{
+		{"empty", "", nil},
+		{
+			"single event",
+			"data: hello\n\n",
+			[]ServerSentEvent{{Data: "hello"}},
+		},
+		{
+			"named event",
+			"event: response.output_text.delta\ndata: {\"delta\":\"hi\"}\n\n",
+			[]ServerSentEvent{{Event: "response.output_text.delta", Data: `{"delta":"hi"}`}},
+		},
+		{
+			"multi-line data",
+			"data: first\ndata: second\ndata:third\n\n",
+			[]ServerSentEvent{{Data: "first\nsecond\nthird"}},
+		},
+		{
+			"comments and blank lines",
+			": keep-alive\n\n\n: another comment\ndata: hello\n\n\n",
+			[]ServerSentEvent{{Data: "hello"}},
+		},
+		{
+			"event without data is dropped",
+			"event: ping\n\ndata: hello\n\n",
+			[]ServerSentEvent{{Data: "hello"}},
+		},
+		{
+			"done marker",
+			"data: {\"text\":\"a\"}\n\ndata: [DONE]\n\n",
+			[]ServerSentEvent{{Data: `{"text":"a"}`}, {Data: "[DONE]"}},
+		},
+		{
+			"crlf line endings",
+			"event: delta\r\ndata: one\r\ndata: two\r\n\r\ndata: three\r\n\r\n",
+			[]ServerSentEvent{{Event: "delta", Data: "one\ntwo"}, {Data: "three"}},
+		},
+		{
+			"no trailing newline",
+			"data: first\n\ndata: last",
+			[]ServerSentEvent{{Data: "first"}, {Data: "last"}},
+		},
+		{
+			"only one leading space is stripped",
+			"data:  indented\n\n",
+			[]ServerSentEvent{{Data: " indented"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []ServerSentEvent
+			err := ReadServerSentEvents(strings.NewReader(tt.stream), func(event ServerSentEvent) error {
+				got = append(got, event)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("failed to read events: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadServerSentEventsHandlerError(t *testing.T) {
+	errStop := errors.New("stop")
+	calls := 0
+	err := ReadServerSentEvents(strings.NewReader("data: one\n\ndata: two\n\n"), func(ServerSentEvent) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("got error %v, want the handler's error", err)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want reading to stop after the first error", calls)
+	}
+}
@@ -1,5 +1,7 @@
 package prompts
 
+import "strings"
+
 const CleanUpPrompt = `
 You are a transcription cleanup assistant. Your job is to clean up transcribed speech by:
 - Removing filler words (um, uh, like, you know, etc.)
@@ -17,3 +19,70 @@ You are a thread title generator in a Transcription application. Create a concis
 - Free of unnecessary words like "Discussion about" or "Conversation regarding"
 
 Output only the title with no preamble or explanation.`
+
+const EmailPrompt = `
+You are a writing assistant. Rewrite the transcribed speech as a clear, well structured email:
+- Open with a short greeting and close with a sign-off, leaving the name as a placeholder
+- Organise the content into short paragraphs
+- Remove filler words, false starts and repetition
+- Keep every request, date and detail the speaker mentioned
+
+Output only the email with no preamble or explanation.`
+
+const BulletPointsPrompt = `
+You are a writing assistant. Summarise the transcribed speech as a list of concise bullet points:
+- One idea, decision or action item per bullet
+- Keep the speaker's wording for names, numbers and technical terms
+- Order the bullets as the topics came up
+
+Output only the bullet points, using "- " for each, with no preamble or explanation.`
+
+const SlackPrompt = `
+You are a writing assistant. Rewrite the transcribed speech as a Slack message:
+- Keep it short, direct and conversational
+- Lead with the main point or ask
+- Use short paragraphs or a few bullet points for longer content
+- Remove filler words, false starts and repetition
+
+Output only the message with no preamble or explanation.`
+
+// Keys of the built-in prompt templates
+const (
+	BuiltinCleanUp      = "cleanup"
+	BuiltinEmail        = "email"
+	BuiltinBulletPoints = "bullet_points"
+	BuiltinSlack        = "slack"
+)
+
+// Builtin is a prompt template shipped with the app
+type Builtin struct {
+	Key    string
+	Name   string
+	Prompt string
+}
+
+// Builtins are seeded as prompt templates on startup
+var Builtins = []Builtin{
+	{Key: BuiltinCleanUp, Name: "Clean up", Prompt: CleanUpPrompt},
+	{Key: BuiltinEmail, Name: "Email", Prompt: EmailPrompt},
+	{Key: BuiltinBulletPoints, Name: "Bullet points", Prompt: BulletPointsPrompt},
+	{Key: BuiltinSlack, Name: "Slack message", Prompt: SlackPrompt},
+}
+
+// WithVocabulary appends a list of terms the model must keep exactly as written,
+// so names and jargon are not "corrected" away
+func WithVocabulary(prompt string, terms []string) string {
+	if len(terms) == 0 {
+		return prompt
+	}
+
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\nThe following terms are spelled correctly. Keep them exactly as written and correct near misses to them:\n")
+	for _, term := range terms {
+		b.WriteString("- ")
+		b.WriteString(term)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
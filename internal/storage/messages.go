@@ -4,7 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"mac-dictation/internal/database"
+	"os"
 	"time"
 )
 
@@ -15,9 +17,15 @@ type Message struct {
 	Text         string     `json:"text"`
 	Provider     string     `json:"provider"`
 	DurationSecs float64    `json:"durationSecs"`
+	AudioPath    string     `json:"audioPath"`
+	Draft        bool       `json:"draft"`
 	CreatedAt    time.Time  `json:"createdAt"`
 	UpdatedAt    time.Time  `json:"updatedAt"`
 	DeletedAt    *time.Time `json:"deletedAt"`
+
+	// Turns and SpeakerText are populated for diarized messages, they are not persisted
+	Turns       []SpeakerTurn `json:"turns,omitempty"`
+	SpeakerText string        `json:"speakerText,omitempty"`
 }
 
 type MessageService struct {
@@ -31,10 +39,10 @@ func NewMessageService(db *database.DB) *MessageService {
 func (m *MessageService) Lookup(id int) (*Message, error) {
 	var msg Message
 	row := m.db.QueryRow(
-		`SELECT id, thread_id, original_text, text, provider, duration_secs, created_at, updated_at, deleted_at
+		`SELECT id, thread_id, original_text, text, provider, duration_secs, COALESCE(audio_path, ''), draft, created_at, updated_at, deleted_at
 			FROM messages WHERE id = $1 AND deleted_at IS NULL`, id)
 
-	err := row.Scan(&msg.ID, &msg.ThreadID, &msg.OriginalText, &msg.Text, &msg.Provider, &msg.DurationSecs, &msg.CreatedAt, &msg.UpdatedAt, &msg.DeletedAt)
+	err := row.Scan(&msg.ID, &msg.ThreadID, &msg.OriginalText, &msg.Text, &msg.Provider, &msg.DurationSecs, &msg.AudioPath, &msg.Draft, &msg.CreatedAt, &msg.UpdatedAt, &msg.DeletedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("message with id %d not found", id)
@@ -45,7 +53,7 @@ func (m *MessageService) Lookup(id int) (*Message, error) {
 
 func (m *MessageService) LookupForThread(threadID int) ([]Message, error) {
 	rows, err := m.db.Query(
-		`SELECT id, thread_id, original_text, text, provider, duration_secs, created_at, updated_at, deleted_at
+		`SELECT id, thread_id, original_text, text, provider, duration_secs, COALESCE(audio_path, ''), draft, created_at, updated_at, deleted_at
 			FROM messages WHERE thread_id = $1 AND deleted_at IS NULL`, threadID)
 	if err != nil {
 		return nil, err
@@ -55,7 +63,7 @@ func (m *MessageService) LookupForThread(threadID int) ([]Message, error) {
 	var messages []Message
 	for rows.Next() {
 		var msg Message
-		err := rows.Scan(&msg.ID, &msg.ThreadID, &msg.OriginalText, &msg.Text, &msg.Provider, &msg.DurationSecs, &msg.CreatedAt, &msg.UpdatedAt, &msg.DeletedAt)
+		err := rows.Scan(&msg.ID, &msg.ThreadID, &msg.OriginalText, &msg.Text, &msg.Provider, &msg.DurationSecs, &msg.AudioPath, &msg.Draft, &msg.CreatedAt, &msg.UpdatedAt, &msg.DeletedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -80,9 +88,9 @@ func (m *MessageService) Persist(msg *Message) error {
 
 		var id int
 		err := m.db.QueryRow(
-			`INSERT INTO messages (thread_id, original_text, text, provider, duration_secs, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
-			msg.ThreadID, msg.OriginalText, msg.Text, msg.Provider, msg.DurationSecs, msg.CreatedAt, msg.UpdatedAt,
+			`INSERT INTO messages (thread_id, original_text, text, provider, duration_secs, audio_path, draft, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			msg.ThreadID, msg.OriginalText, msg.Text, msg.Provider, msg.DurationSecs, msg.AudioPath, msg.Draft, msg.CreatedAt, msg.UpdatedAt,
 		).Scan(&id)
 		if err != nil {
 			return err
@@ -99,20 +107,89 @@ func (m *MessageService) Persist(msg *Message) error {
 	msg.UpdatedAt = now
 	_, err = m.db.Exec(
 		`UPDATE messages
-			 SET original_text= $1, text = $2, provider = $3, duration_secs = $4, updated_at = $5, deleted_at = $6
-			 WHERE id = $7 AND deleted_at IS NULL`,
-		msg.OriginalText, msg.Text, msg.Provider, msg.DurationSecs, msg.UpdatedAt, msg.DeletedAt, *msg.ID,
+			 SET original_text= $1, text = $2, provider = $3, duration_secs = $4, audio_path = $5, draft = $6, updated_at = $7, deleted_at = $8
+			 WHERE id = $9 AND deleted_at IS NULL`,
+		msg.OriginalText, msg.Text, msg.Provider, msg.DurationSecs, msg.AudioPath, msg.Draft, msg.UpdatedAt, msg.DeletedAt, *msg.ID,
 	)
 	return err
 }
 
+// Delete soft deletes the message and removes its audio recording from disk
 func (m *MessageService) Delete(id int) error {
 	msg, err := m.Lookup(id)
 	if err != nil {
 		return err
 	}
 
+	// Save the delete before removing the audio, so a failed save never
+	// leaves a message pointing at a file that is gone
+	audioPath := msg.AudioPath
 	now := time.Now().UTC()
 	msg.DeletedAt = &now
-	return m.Persist(msg)
+	msg.AudioPath = ""
+	if err := m.Persist(msg); err != nil {
+		return err
+	}
+
+	removeAudio(audioPath)
+	return nil
+}
+
+// DeleteForThread soft deletes all messages belonging to a thread in one
+// transaction, then removes their audio recordings from disk
+func (m *MessageService) DeleteForThread(threadID int) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx *sql.Tx) {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			slog.Error("failed to rollback transaction", "error", err)
+		}
+	}(tx)
+
+	rows, err := tx.Query(
+		`SELECT audio_path FROM messages WHERE thread_id = $1 AND deleted_at IS NULL AND audio_path != ''`, threadID)
+	if err != nil {
+		return err
+	}
+	var audioPaths []string
+	for rows.Next() {
+		var audioPath string
+		if err := rows.Scan(&audioPath); err != nil {
+			rows.Close()
+			return err
+		}
+		audioPaths = append(audioPaths, audioPath)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	_, err = tx.Exec(
+		`UPDATE messages SET audio_path = '', deleted_at = $1, updated_at = $1
+			WHERE thread_id = $2 AND deleted_at IS NULL`, now, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to delete thread messages: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, audioPath := range audioPaths {
+		removeAudio(audioPath)
+	}
+	return nil
+}
+
+// removeAudio deletes a message's recording, once the message no longer refers to it
+func removeAudio(audioPath string) {
+	if audioPath == "" {
+		return
+	}
+	if err := os.Remove(audioPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Error("failed to remove message audio", "error", err, "path", audioPath)
+	}
 }
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMessageDeleteRemovesAudio(t *testing.T) {
+	db := newTestDB(t)
+	messages := NewMessageService(db)
+	message := newTestMessage(t, db, nil)
+
+	audioPath := filepath.Join(t.TempDir(), "message.wav")
+	if err := os.WriteFile(audioPath, []byte("RIFF"), 0o644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+	message.AudioPath = audioPath
+	if err := messages.Persist(message); err != nil {
+		t.Fatalf("failed to persist message: %v", err)
+	}
+
+	if err := messages.Delete(*message.ID); err != nil {
+		t.Fatalf("failed to delete message: %v", err)
+	}
+	if _, err := messages.Lookup(*message.ID); err == nil {
+		t.Error("deleted message can still be looked up")
+	}
+	if _, err := os.Stat(audioPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("audio still on disk after delete: %v", err)
+	}
+
+	// Deleting again fails without touching anything
+	if err := messages.Delete(*message.ID); err == nil {
+		t.Error("expected deleting a deleted message to fail")
+	}
+}
+
+func TestMessageDeleteForThread(t *testing.T) {
+	db := newTestDB(t)
+	messages := NewMessageService(db)
+	first := newTestMessage(t, db, nil)
+	second := newTestMessage(t, db, &first.ThreadID)
+	other := newTestMessage(t, db, nil)
+
+	audioPath := filepath.Join(t.TempDir(), "second.wav")
+	if err := os.WriteFile(audioPath, []byte("RIFF"), 0o644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+	second.AudioPath = audioPath
+	if err := messages.Persist(second); err != nil {
+		t.Fatalf("failed to persist message: %v", err)
+	}
+
+	if err := messages.DeleteForThread(first.ThreadID); err != nil {
+		t.Fatalf("failed to delete thread messages: %v", err)
+	}
+	if left, err := messages.LookupForThread(first.ThreadID); err != nil || len(left) != 0 {
+		t.Errorf("%d messages left in the thread (err %v), want none", len(left), err)
+	}
+	if _, err := os.Stat(audioPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("audio still on disk after delete: %v", err)
+	}
+	if _, err := messages.Lookup(*other.ID); err != nil {
+		t.Errorf("message in another thread was deleted: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"mac-dictation/internal/database"
+	"strings"
+	"time"
+)
+
+// PromptTemplate is a named instruction run against a message's text, such as
+// "rewrite as an email". Built-in templates are seeded at startup and keep
+// their BuiltinKey, user created templates have none.
+type PromptTemplate struct {
+	ID         *int      `json:"id"`
+	BuiltinKey string    `json:"builtinKey"`
+	Name       string    `json:"name"`
+	Prompt     string    `json:"prompt"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// IsBuiltin reports whether the template was seeded by the app
+func (t *PromptTemplate) IsBuiltin() bool {
+	return t.BuiltinKey != ""
+}
+
+type PromptTemplateService struct {
+	db *database.DB
+}
+
+func NewPromptTemplateService(db *database.DB) *PromptTemplateService {
+	return &PromptTemplateService{db}
+}
+
+const promptTemplateColumns = `id, COALESCE(builtin_key, ''), name, prompt, created_at, updated_at`
+
+func scanPromptTemplate(row interface{ Scan(...any) error }) (*PromptTemplate, error) {
+	var template PromptTemplate
+	err := row.Scan(&template.ID, &template.BuiltinKey, &template.Name, &template.Prompt, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (p *PromptTemplateService) Lookup(id int) (*PromptTemplate, error) {
+	template, err := scanPromptTemplate(p.db.QueryRow(
+		`SELECT `+promptTemplateColumns+` FROM prompt_templates WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("prompt template with id %d not found", id)
+	}
+	return template, err
+}
+
+// LookupBuiltin returns the built-in template seeded under key
+func (p *PromptTemplateService) LookupBuiltin(key string) (*PromptTemplate, error) {
+	template, err := scanPromptTemplate(p.db.QueryRow(
+		`SELECT `+promptTemplateColumns+` FROM prompt_templates WHERE builtin_key = $1`, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("built-in prompt template %q not found", key)
+	}
+	return template, err
+}
+
+// LookupAll returns every template, built-ins first
+func (p *PromptTemplateService) LookupAll() ([]PromptTemplate, error) {
+	rows, err := p.db.Query(
+		`SELECT ` + promptTemplateColumns + ` FROM prompt_templates
+			ORDER BY builtin_key IS NULL, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []PromptTemplate
+	for rows.Next() {
+		template, err := scanPromptTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, rows.Err()
+}
+
+// SeedBuiltins inserts any built-in templates not already present. Existing
+// built-ins are left as they are, so edits made by the user are kept.
+func (p *PromptTemplateService) SeedBuiltins(templates []PromptTemplate) error {
+	now := time.Now().UTC()
+	for _, template := range templates {
+		_, err := p.db.Exec(
+			`INSERT OR IGNORE INTO prompt_templates (builtin_key, name, prompt, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5)`,
+			template.BuiltinKey, template.Name, strings.TrimSpace(template.Prompt), now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to seed prompt template %q: %w", template.BuiltinKey, err)
+		}
+	}
+	return nil
+}
+
+func (p *PromptTemplateService) Persist(template *PromptTemplate) error {
+	if template == nil {
+		return fmt.Errorf("prompt template is nil")
+	}
+
+	template.Name = strings.TrimSpace(template.Name)
+	template.Prompt = strings.TrimSpace(template.Prompt)
+	if template.Name == "" {
+		return fmt.Errorf("prompt template name is empty")
+	}
+	if template.Prompt == "" {
+		return fmt.Errorf("prompt template prompt is empty")
+	}
+
+	now := time.Now().UTC()
+
+	if template.ID == nil {
+		if template.CreatedAt.IsZero() {
+			template.CreatedAt = now
+		}
+		template.UpdatedAt = now
+
+		var id int
+		err := p.db.QueryRow(
+			`INSERT INTO prompt_templates (name, prompt, created_at, updated_at)
+				VALUES ($1, $2, $3, $4) RETURNING id`,
+			template.Name, template.Prompt, template.CreatedAt, template.UpdatedAt,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		template.ID = &id
+		return nil
+	}
+
+	_, err := p.Lookup(*template.ID)
+	if err != nil {
+		return err
+	}
+
+	template.UpdatedAt = now
+	_, err = p.db.Exec(
+		`UPDATE prompt_templates SET name = $1, prompt = $2, updated_at = $3 WHERE id = $4`,
+		template.Name, template.Prompt, template.UpdatedAt, *template.ID,
+	)
+	return err
+}
+
+// Delete removes a user created template. Built-in templates cannot be deleted,
+// as they would be seeded again on the next launch.
+func (p *PromptTemplateService) Delete(id int) error {
+	template, err := p.Lookup(id)
+	if err != nil {
+		return err
+	}
+	if template.IsBuiltin() {
+		return fmt.Errorf("built-in prompt template %q cannot be deleted", template.Name)
+	}
+
+	_, err = p.db.Exec(`DELETE FROM prompt_templates WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mac-dictation/internal/database"
+	"time"
+)
+
+// RevisionSource records what produced a revision of a message's text
+type RevisionSource string
+
+const (
+	// RevisionTranscript is the text as transcribed
+	RevisionTranscript RevisionSource = "transcript"
+	// RevisionLLM is text produced by an LLM, with the prompt and model recorded
+	RevisionLLM RevisionSource = "llm"
+	// RevisionTemplate is text produced by running a prompt template with an
+	// LLM. It is kept alongside the history and never becomes the head.
+	RevisionTemplate RevisionSource = "template"
+	// RevisionCleanup is text tidied by the rule-based cleanup, without an LLM
+	RevisionCleanup RevisionSource = "cleanup"
+	// RevisionManual is text edited by hand
+	RevisionManual RevisionSource = "manual"
+	// RevisionRestore is an earlier revision made current again
+	RevisionRestore RevisionSource = "restore"
+)
+
+// MessageRevision is one version of a message's text. The latest revision that
+// is not a template run is the message's head, mirrored in Message.Text.
+type MessageRevision struct {
+	ID        *int           `json:"id"`
+	MessageID int            `json:"messageId"`
+	Text      string         `json:"text"`
+	Source    RevisionSource `json:"source"`
+	Prompt    string         `json:"prompt"`
+	Model     string         `json:"model"`
+	// TemplateName is the prompt template a template revision ran, kept for display
+	TemplateName string `json:"templateName"`
+	// RestoredFrom is the revision a restore copied
+	RestoredFrom *int      `json:"restoredFrom"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type MessageRevisionService struct {
+	db *database.DB
+}
+
+func NewMessageRevisionService(db *database.DB) *MessageRevisionService {
+	return &MessageRevisionService{db}
+}
+
+func (m *MessageRevisionService) Lookup(id int) (*MessageRevision, error) {
+	var r MessageRevision
+	row := m.db.QueryRow(
+		`SELECT id, message_id, text, source, COALESCE(prompt, ''), COALESCE(model, ''), COALESCE(template_name, ''), restored_from, created_at
+			FROM message_revisions WHERE id = $1`, id)
+
+	err := row.Scan(&r.ID, &r.MessageID, &r.Text, &r.Source, &r.Prompt, &r.Model, &r.TemplateName, &r.RestoredFrom, &r.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("message revision with id %d not found", id)
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// LookupForMessage returns a message's revisions, oldest first
+func (m *MessageRevisionService) LookupForMessage(messageID int) ([]MessageRevision, error) {
+	rows, err := m.db.Query(
+		`SELECT id, message_id, text, source, COALESCE(prompt, ''), COALESCE(model, ''), COALESCE(template_name, ''), restored_from, created_at
+			FROM message_revisions WHERE message_id = $1 ORDER BY id`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []MessageRevision
+	for rows.Next() {
+		var r MessageRevision
+		if err := rows.Scan(&r.ID, &r.MessageID, &r.Text, &r.Source, &r.Prompt, &r.Model, &r.TemplateName, &r.RestoredFrom, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+
+	return revisions, rows.Err()
+}
+
+// Persist stores a new revision. Revisions are never updated.
+func (m *MessageRevisionService) Persist(r *MessageRevision) error {
+	return insertRevision(m.db, r)
+}
+
+// PersistHead stores a new revision and makes it the message's current text
+// in one transaction, so the head never disagrees with the latest revision
+func (m *MessageRevisionService) PersistHead(r *MessageRevision) (err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx *sql.Tx) {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			slog.Error("failed to rollback transaction", "error", err)
+		}
+	}(tx)
+
+	if err := insertRevision(tx, r); err != nil {
+		return err
+	}
+	// The inserted revision is rolled back with everything else on failure
+	defer func() {
+		if err != nil {
+			r.ID = nil
+		}
+	}()
+
+	res, err := tx.Exec(
+		`UPDATE messages SET text = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`,
+		r.Text, r.CreatedAt, r.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to update message text: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("message with id %d not found", r.MessageID)
+	}
+
+	return tx.Commit()
+}
+
+// queryRower is a database or transaction a revision can be inserted with
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func insertRevision(db queryRower, r *MessageRevision) error {
+	if r == nil {
+		return fmt.Errorf("message revision is nil")
+	}
+	if r.ID != nil {
+		return fmt.Errorf("message revision %d already exists", *r.ID)
+	}
+
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+
+	var id int
+	err := db.QueryRow(
+		`INSERT INTO message_revisions (message_id, text, source, prompt, model, template_name, restored_from, created_at)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8) RETURNING id`,
+		r.MessageID, r.Text, r.Source, r.Prompt, r.Model, r.TemplateName, r.RestoredFrom, r.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return err
+	}
+	r.ID = &id
+	return nil
+}
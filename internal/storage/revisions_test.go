@@ -0,0 +1,77 @@
+package storage
+
+import "testing"
+
+func TestMessageRevisionTemplateRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+	revisions := NewMessageRevisionService(db)
+	message := newTestMessage(t, db, nil)
+
+	revision := &MessageRevision{
+		MessageID:    *message.ID,
+		Text:         "- hello there",
+		Source:       RevisionTemplate,
+		Prompt:       "Turn this into a list",
+		Model:        "gpt-4o-mini",
+		TemplateName: "Bullet points",
+	}
+	if err := revisions.Persist(revision); err != nil {
+		t.Fatalf("failed to persist revision: %v", err)
+	}
+	plain := &MessageRevision{MessageID: *message.ID, Text: "hello there", Source: RevisionManual}
+	if err := revisions.Persist(plain); err != nil {
+		t.Fatalf("failed to persist revision: %v", err)
+	}
+
+	got, err := revisions.LookupForMessage(*message.ID)
+	if err != nil {
+		t.Fatalf("failed to look up revisions: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(got))
+	}
+	if got[0].Source != RevisionTemplate || got[0].TemplateName != "Bullet points" || got[0].Model != "gpt-4o-mini" {
+		t.Errorf("template revision %+v, want its source, template and model kept", got[0])
+	}
+	if got[1].TemplateName != "" {
+		t.Errorf("manual revision has template %q, want none", got[1].TemplateName)
+	}
+}
+
+func TestMessageRevisionPersistHead(t *testing.T) {
+	db := newTestDB(t)
+	revisions := NewMessageRevisionService(db)
+	messages := NewMessageService(db)
+	message := newTestMessage(t, db, nil)
+
+	revision := &MessageRevision{MessageID: *message.ID, Text: "Hello there.", Source: RevisionManual}
+	if err := revisions.PersistHead(revision); err != nil {
+		t.Fatalf("failed to persist head: %v", err)
+	}
+	got, err := messages.Lookup(*message.ID)
+	if err != nil {
+		t.Fatalf("failed to look up message: %v", err)
+	}
+	if got.Text != "Hello there." {
+		t.Errorf("message text %q, want the new head", got.Text)
+	}
+
+	// A deleted message gets no new head, and the revision is rolled back
+	if err := messages.Delete(*message.ID); err != nil {
+		t.Fatalf("failed to delete message: %v", err)
+	}
+	orphan := &MessageRevision{MessageID: *message.ID, Text: "Too late.", Source: RevisionManual}
+	if err := revisions.PersistHead(orphan); err == nil {
+		t.Fatal("expected a head on a deleted message to be rejected")
+	}
+	if orphan.ID != nil {
+		t.Error("rolled back revision kept its id")
+	}
+	all, err := revisions.LookupForMessage(*message.ID)
+	if err != nil {
+		t.Fatalf("failed to look up revisions: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("got %d revisions, want the rolled back one gone", len(all))
+	}
+}
@@ -3,7 +3,9 @@ package storage
 import (
 	"database/sql"
 	"errors"
+	"log/slog"
 	"mac-dictation/internal/database"
+	"strconv"
 	"time"
 )
 
@@ -34,6 +36,36 @@ func (s *SettingsService) Get(key string) (string, error) {
 	return value, nil
 }
 
+// GetBool returns the setting parsed as a bool, or fallback if unset or invalid
+func (s *SettingsService) GetBool(key string, fallback bool) bool {
+	value, err := s.Get(key)
+	if err != nil || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("invalid bool setting", "key", key, "value", value)
+		return fallback
+	}
+	return parsed
+}
+
+// GetFloat returns the setting parsed as a float, or fallback if unset or invalid
+func (s *SettingsService) GetFloat(key string, fallback float64) float64 {
+	value, err := s.Get(key)
+	if err != nil || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Warn("invalid float setting", "key", key, "value", value)
+		return fallback
+	}
+	return parsed
+}
+
 func (s *SettingsService) Set(key, value string) error {
 	now := time.Now().UTC()
 
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+	"mac-dictation/internal/database"
+	"strings"
+	"time"
+)
+
+// SpeakerTurn is a run of consecutive words spoken by the same speaker
+type SpeakerTurn struct {
+	Speaker int     `json:"speaker"`
+	Label   string  `json:"label"`
+	Text    string  `json:"text"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+}
+
+// ThreadSpeakerService stores per thread names for diarized speakers
+type ThreadSpeakerService struct {
+	db *database.DB
+}
+
+func NewThreadSpeakerService(db *database.DB) *ThreadSpeakerService {
+	return &ThreadSpeakerService{db}
+}
+
+// LookupForThread returns speaker names keyed by speaker number
+func (s *ThreadSpeakerService) LookupForThread(threadID int) (map[int]string, error) {
+	rows, err := s.db.Query(`SELECT speaker, name FROM thread_speakers WHERE thread_id = $1`, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[int]string)
+	for rows.Next() {
+		var speaker int
+		var name string
+		if err := rows.Scan(&speaker, &name); err != nil {
+			return nil, err
+		}
+		names[speaker] = name
+	}
+	return names, rows.Err()
+}
+
+// Rename sets the name of a speaker in a thread. An empty name restores the default label
+func (s *ThreadSpeakerService) Rename(threadID, speaker int, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		_, err := s.db.Exec(`DELETE FROM thread_speakers WHERE thread_id = $1 AND speaker = $2`, threadID, speaker)
+		return err
+	}
+
+	now := time.Now().UTC()
+	_, err := s.db.Exec(
+		`INSERT INTO thread_speakers (thread_id, speaker, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (thread_id, speaker) DO UPDATE SET name = excluded.name, updated_at = excluded.updated_at`,
+		threadID, speaker, name, now, now,
+	)
+	return err
+}
+
+// SpeakerLabel returns the display name of a speaker, defaulting to "Speaker N"
+func SpeakerLabel(speaker int, names map[int]string) string {
+	if name, ok := names[speaker]; ok {
+		return name
+	}
+	// Deepgram numbers speakers from 0
+	return fmt.Sprintf("Speaker %d", speaker+1)
+}
+
+// BuildSpeakerTurns groups diarized words into speaker turns.
+// Returns nil if the words carry no speaker information.
+func BuildSpeakerTurns(words []MessageWord, names map[int]string) []SpeakerTurn {
+	var turns []SpeakerTurn
+	var text []string
+
+	flush := func() {
+		if len(turns) > 0 {
+			turns[len(turns)-1].Text = strings.Join(text, " ")
+		}
+		text = text[:0]
+	}
+
+	for _, word := range words {
+		if word.Speaker == nil {
+			continue
+		}
+
+		if len(turns) == 0 || turns[len(turns)-1].Speaker != *word.Speaker {
+			flush()
+			turns = append(turns, SpeakerTurn{
+				Speaker: *word.Speaker,
+				Label:   SpeakerLabel(*word.Speaker, names),
+				Start:   word.Start,
+			})
+		}
+
+		turns[len(turns)-1].End = word.End
+		text = append(text, word.Word)
+	}
+	flush()
+
+	return turns
+}
+
+// SpeakerTranscript renders turns as "Speaker 1: ..." lines
+func SpeakerTranscript(turns []SpeakerTurn) string {
+	lines := make([]string, 0, len(turns))
+	for _, turn := range turns {
+		lines = append(lines, turn.Label+": "+turn.Text)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"mac-dictation/internal/database"
+	"strings"
+	"time"
+)
+
+// VocabularyTerm is a product name, person or piece of jargon that
+// transcription and cleanup should preserve as written
+type VocabularyTerm struct {
+	ID        *int      `json:"id"`
+	Term      string    `json:"term"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type VocabularyService struct {
+	db *database.DB
+}
+
+func NewVocabularyService(db *database.DB) *VocabularyService {
+	return &VocabularyService{db}
+}
+
+func (v *VocabularyService) Lookup(id int) (*VocabularyTerm, error) {
+	var term VocabularyTerm
+	row := v.db.QueryRow(
+		`SELECT id, term, created_at, updated_at FROM vocabulary WHERE id = $1`, id)
+
+	err := row.Scan(&term.ID, &term.Term, &term.CreatedAt, &term.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("vocabulary term with id %d not found", id)
+		}
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (v *VocabularyService) LookupAll() ([]VocabularyTerm, error) {
+	rows, err := v.db.Query(
+		`SELECT id, term, created_at, updated_at FROM vocabulary ORDER BY term COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terms []VocabularyTerm
+	for rows.Next() {
+		var term VocabularyTerm
+		if err := rows.Scan(&term.ID, &term.Term, &term.CreatedAt, &term.UpdatedAt); err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, rows.Err()
+}
+
+// Terms returns just the text of every vocabulary term
+func (v *VocabularyService) Terms() ([]string, error) {
+	all, err := v.LookupAll()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make([]string, 0, len(all))
+	for _, term := range all {
+		terms = append(terms, term.Term)
+	}
+	return terms, nil
+}
+
+func (v *VocabularyService) Persist(term *VocabularyTerm) error {
+	if term == nil {
+		return fmt.Errorf("vocabulary term is nil")
+	}
+
+	term.Term = strings.TrimSpace(term.Term)
+	if term.Term == "" {
+		return fmt.Errorf("vocabulary term is empty")
+	}
+
+	now := time.Now().UTC()
+
+	if term.ID == nil {
+		if term.CreatedAt.IsZero() {
+			term.CreatedAt = now
+		}
+		term.UpdatedAt = now
+
+		var id int
+		err := v.db.QueryRow(
+			`INSERT INTO vocabulary (term, created_at, updated_at)
+				VALUES ($1, $2, $3) RETURNING id`, term.Term, term.CreatedAt, term.UpdatedAt,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		term.ID = &id
+		return nil
+	}
+
+	_, err := v.Lookup(*term.ID)
+	if err != nil {
+		return err
+	}
+
+	term.UpdatedAt = now
+	_, err = v.db.Exec(
+		`UPDATE vocabulary SET term = $1, updated_at = $2 WHERE id = $3`,
+		term.Term, term.UpdatedAt, *term.ID,
+	)
+	return err
+}
+
+func (v *VocabularyService) Delete(id int) error {
+	_, err := v.db.Exec(`DELETE FROM vocabulary WHERE id = $1`, id)
+	return err
+}
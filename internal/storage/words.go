@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mac-dictation/internal/database"
+)
+
+// MessageWord is a single transcribed word of a message, with start and end
+// in seconds from the start of the message audio
+type MessageWord struct {
+	Word       string  `json:"word"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Confidence float64 `json:"confidence"`
+	// Speaker is set when the transcription was diarized
+	Speaker *int `json:"speaker,omitempty"`
+}
+
+type MessageWordService struct {
+	db *database.DB
+}
+
+func NewMessageWordService(db *database.DB) *MessageWordService {
+	return &MessageWordService{db}
+}
+
+// LookupForMessage returns the words of a message in spoken order
+func (w *MessageWordService) LookupForMessage(messageID int) ([]MessageWord, error) {
+	rows, err := w.db.Query(
+		`SELECT word, start_secs, end_secs, confidence, speaker
+			FROM message_words WHERE message_id = $1 ORDER BY position`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []MessageWord
+	for rows.Next() {
+		var word MessageWord
+		if err := rows.Scan(&word.Word, &word.Start, &word.End, &word.Confidence, &word.Speaker); err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+
+	return words, rows.Err()
+}
+
+// LookupDiarizedForThread returns the words of each message in a thread that has
+// speaker labels, keyed by message ID. Messages that were not diarized are left
+// out, so a thread is loaded in one query however many messages it has.
+func (w *MessageWordService) LookupDiarizedForThread(threadID int) (map[int][]MessageWord, error) {
+	rows, err := w.db.Query(
+		`SELECT message_id, word, start_secs, end_secs, confidence, speaker
+			FROM message_words
+			WHERE message_id IN (
+				SELECT DISTINCT mw.message_id FROM message_words mw
+					JOIN messages m ON m.id = mw.message_id
+					WHERE m.thread_id = $1 AND m.deleted_at IS NULL AND mw.speaker IS NOT NULL)
+			ORDER BY message_id, position`, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := make(map[int][]MessageWord)
+	for rows.Next() {
+		var messageID int
+		var word MessageWord
+		if err := rows.Scan(&messageID, &word.Word, &word.Start, &word.End, &word.Confidence, &word.Speaker); err != nil {
+			return nil, err
+		}
+		words[messageID] = append(words[messageID], word)
+	}
+
+	return words, rows.Err()
+}
+
+// PersistForMessage replaces the stored words of a message
+func (w *MessageWordService) PersistForMessage(messageID int, words []MessageWord) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx *sql.Tx) {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			slog.Error("failed to rollback transaction", "error", err)
+		}
+	}(tx)
+
+	if _, err := tx.Exec(`DELETE FROM message_words WHERE message_id = $1`, messageID); err != nil {
+		return fmt.Errorf("failed to clear message words: %w", err)
+	}
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO message_words (message_id, position, word, start_secs, end_secs, confidence, speaker)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, word := range words {
+		if _, err := stmt.Exec(messageID, i, word.Word, word.Start, word.End, word.Confidence, word.Speaker); err != nil {
+			return fmt.Errorf("failed to insert message word: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"mac-dictation/internal/database"
+)
+
+// newTestDB opens a migrated database in a temporary directory
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+
+	db, err := database.Connect(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := database.RunMigrations(context.Background(), db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	return db
+}
+
+// newTestMessage persists a message in a new or existing thread
+func newTestMessage(t *testing.T, db *database.DB, threadID *int) *Message {
+	t.Helper()
+
+	if threadID == nil {
+		thread := &Thread{Name: "Test"}
+		if err := NewThreadService(db).Persist(thread); err != nil {
+			t.Fatalf("failed to persist thread: %v", err)
+		}
+		threadID = thread.ID
+	}
+
+	message := &Message{ThreadID: *threadID, OriginalText: "hello there", Text: "hello there"}
+	if err := NewMessageService(db).Persist(message); err != nil {
+		t.Fatalf("failed to persist message: %v", err)
+	}
+	return message
+}
+
+func TestLookupDiarizedForThread(t *testing.T) {
+	db := newTestDB(t)
+	words := NewMessageWordService(db)
+	speaker := 1
+
+	diarized := newTestMessage(t, db, nil)
+	plain := newTestMessage(t, db, &diarized.ThreadID)
+	deleted := newTestMessage(t, db, &diarized.ThreadID)
+	other := newTestMessage(t, db, nil)
+
+	labelled := []MessageWord{
+		{Word: "hello", Start: 0, End: 0.5, Speaker: &speaker},
+		{Word: "there", Start: 0.5, End: 1, Speaker: &speaker},
+	}
+	for _, id := range []int{*diarized.ID, *deleted.ID, *other.ID} {
+		if err := words.PersistForMessage(id, labelled); err != nil {
+			t.Fatalf("failed to persist words: %v", err)
+		}
+	}
+	if err := words.PersistForMessage(*plain.ID, []MessageWord{{Word: "hello"}, {Word: "there"}}); err != nil {
+		t.Fatalf("failed to persist words: %v", err)
+	}
+	if err := NewMessageService(db).Delete(*deleted.ID); err != nil {
+		t.Fatalf("failed to delete message: %v", err)
+	}
+
+	got, err := words.LookupDiarizedForThread(diarized.ThreadID)
+	if err != nil {
+		t.Fatalf("failed to look up words: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got words for %d messages, want only the diarized message", len(got))
+	}
+	if w := got[*diarized.ID]; len(w) != 2 || w[0].Word != "hello" || w[1].Word != "there" {
+		t.Fatalf("diarized message words %+v, want hello there in order", w)
+	}
+}
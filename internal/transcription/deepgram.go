@@ -2,38 +2,19 @@ package transcription
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"strings"
-	"sync"
 	"time"
-
-	"github.com/gorilla/websocket"
 )
 
-type Provider interface {
-	StartStream() error
-	SendChunk(data []byte) error
-	OnResult(callback func(message string, isFinal bool))
-	EndStream() (string, error)
-
-	// Transcribe sends audio to Deepgram API and returns transcription string synchronously
-	Transcribe(audioData []byte) (string, error)
-}
-
+// DeepgramService creates Deepgram streaming sessions and batch transcriptions
 type DeepgramService struct {
-	apiKey string
-
-	conn     *websocket.Conn
-	done     chan struct{}
-	err      chan error
-	onResult func(transcript string, isFinal bool)
-
-	mu         sync.Mutex
-	transcript strings.Builder
+	apiKey  string
+	options DeepgramOptions
 }
 
 var _ Provider = &DeepgramService{}
@@ -42,6 +23,7 @@ type MessageType string
 
 const (
 	CloseStream  MessageType = "CloseStream"
+	KeepAlive    MessageType = "KeepAlive"
 	Results      MessageType = "Results"
 	UtteranceEnd MessageType = "UtteranceEnd"
 )
@@ -51,148 +33,76 @@ type Message struct {
 }
 
 type DeepgramStreamingResponse struct {
-	Type    string `json:"type"`
-	IsFinal bool   `json:"is_final"`
-	Channel struct {
-		Alternatives []struct {
-			Transcript string `json:"transcript"`
-		} `json:"alternatives"`
+	Type     string  `json:"type"`
+	IsFinal  bool    `json:"is_final"`
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+	Channel  struct {
+		Alternatives []DeepgramAlternative `json:"alternatives"`
 	} `json:"channel"`
 }
 
-func (s *DeepgramService) StartStream() error {
-	url := "wss://api.deepgram.com/v1/listen?punctuate=true&language=en-GB&model=nova-3&encoding=linear16&sample_rate=16000&utterance_end_ms=5000&interim_results=true"
-	headers := http.Header{}
-	headers.Set("Authorization", "Token "+s.apiKey)
-
-	s.done = make(chan struct{})
-	s.err = make(chan error, 1)
-
-	c, _, err := websocket.DefaultDialer.Dial(url, headers)
-	if err != nil {
-		return fmt.Errorf("failed to connect to Deepgram API: %w", err)
-	}
-
-	s.conn = c
-
-	go func() {
-		defer close(s.done)
-		for {
-			_, message, err := c.ReadMessage()
-			if err != nil {
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
-					return
-				}
-				slog.Error("Failed to read message:", err)
-				s.err <- fmt.Errorf("failed to read message: %w", err)
-				return
-			}
-
-			slog.Debug("Received raw message", "message", string(message))
-
-			var msg Message
-			if err := json.Unmarshal(message, &msg); err != nil {
-				slog.Error("Failed to unmarshal message:", err)
-				s.err <- fmt.Errorf("failed to unmarshal message: %w", err)
-				return
-			}
-
-			switch msg.Type {
-			case string(Results):
-				var result DeepgramStreamingResponse
-				if err := json.Unmarshal(message, &result); err != nil {
-					slog.Error("Failed to unmarshal message:", err)
-					s.err <- fmt.Errorf("failed to unmarshal result message: %w", err)
-					return
-				}
-
-				if len(result.Channel.Alternatives) == 0 {
-					continue
-				}
-				transcript := result.Channel.Alternatives[0].Transcript
-				if s.onResult != nil && transcript != "" {
-					s.onResult(transcript, result.IsFinal)
-				}
-
-				if result.IsFinal && transcript != "" {
-					s.mu.Lock()
-					if s.transcript.Len() > 0 {
-						s.transcript.WriteString(" ")
-					}
-					s.transcript.WriteString(transcript)
-					s.mu.Unlock()
-				}
-			case string(UtteranceEnd):
-				s.mu.Lock()
-				s.transcript.WriteString("\n")
-				s.mu.Unlock()
-				// TODO: Should we close?
-			}
-		}
-	}()
-
-	return nil
-}
-func (s *DeepgramService) SendChunk(data []byte) error {
-	if s.conn == nil {
-		return fmt.Errorf("connection not started")
-	}
-	return s.conn.WriteMessage(websocket.BinaryMessage, data)
+type DeepgramAlternative struct {
+	Transcript string         `json:"transcript"`
+	Words      []DeepgramWord `json:"words"`
 }
 
-func (s *DeepgramService) OnResult(callback func(message string, isFinal bool)) {
-	s.onResult = callback
+type DeepgramWord struct {
+	Word           string  `json:"word"`
+	PunctuatedWord string  `json:"punctuated_word"`
+	Start          float64 `json:"start"`
+	End            float64 `json:"end"`
+	Confidence     float64 `json:"confidence"`
+	Speaker        *int    `json:"speaker"`
 }
 
-func (s *DeepgramService) EndStream() (string, error) {
-	if s.conn == nil {
-		return "", fmt.Errorf("connection not started")
-	}
-	err := s.sendMessage(CloseStream)
-	if err != nil {
-		return "", err
-	}
-
-	<-s.done
-
-	select {
-	case err := <-s.err:
-		s.conn.Close()
-		s.conn = nil
-		return "", err
-	default:
+// toWords converts Deepgram words, preferring the punctuated form when present
+func toWords(words []DeepgramWord) []Word {
+	converted := make([]Word, 0, len(words))
+	for _, w := range words {
+		text := w.PunctuatedWord
+		if text == "" {
+			text = w.Word
+		}
+		converted = append(converted, Word{
+			Word:       text,
+			Start:      w.Start,
+			End:        w.End,
+			Confidence: w.Confidence,
+			Speaker:    w.Speaker,
+		})
 	}
-
-	s.conn.Close()
-	s.conn = nil
-
-	s.mu.Lock()
-	result := s.transcript.String()
-	s.transcript.Reset()
-	s.mu.Unlock()
-
-	return result, nil
+	return converted
 }
 
-func (s *DeepgramService) sendMessage(messageType MessageType) error {
-	return s.conn.WriteJSON(Message{string(messageType)})
+// StartStream opens a Deepgram websocket session
+func (s *DeepgramService) StartStream(ctx context.Context) (Session, error) {
+	session := newDeepgramSession(ctx, s.apiKey, s.options)
+	if err := session.start(); err != nil {
+		session.cancel()
+		return nil, err
+	}
+	return session, nil
 }
 
-func NewDeepgramService(apiKey string) *DeepgramService {
-	return &DeepgramService{apiKey, nil, make(chan struct{}), make(chan error, 1), nil, sync.Mutex{}, strings.Builder{}}
+func NewDeepgramService(apiKey string, options DeepgramOptions) *DeepgramService {
+	return &DeepgramService{
+		apiKey:  apiKey,
+		options: options,
+	}
 }
 
-// Transcribe sends audio to Deepgram API and returns transcription string
-func (s *DeepgramService) Transcribe(audioData []byte) (string, error) {
+// Transcribe sends audio to Deepgram API and returns the transcription
+func (s *DeepgramService) Transcribe(ctx context.Context, audioData []byte) (Result, error) {
 	if s.apiKey == "" {
-		return "", fmt.Errorf("missing deepgram API Key")
+		return Result{}, fmt.Errorf("missing deepgram API Key")
 	}
 
-	url := "https://api.deepgram.com/v1/listen?model=nova-3&language=en-GB&smart_format=true&encoding=linear16&sample_rate=16000&channels=1"
+	url := s.options.batchURL()
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(audioData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(audioData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Token "+s.apiKey)
@@ -201,7 +111,7 @@ func (s *DeepgramService) Transcribe(audioData []byte) (string, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return Result{}, fmt.Errorf("request failed: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -212,33 +122,32 @@ func (s *DeepgramService) Transcribe(audioData []byte) (string, error) {
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return Result{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return Result{}, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var result DeepgramResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return Result{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if len(result.Results.Channels) > 0 &&
 		len(result.Results.Channels[0].Alternatives) > 0 {
-		return result.Results.Channels[0].Alternatives[0].Transcript, nil
+		alternative := result.Results.Channels[0].Alternatives[0]
+		return Result{Text: alternative.Transcript, Words: toWords(alternative.Words)}, nil
 	}
 
-	return "", nil
+	return Result{}, nil
 }
 
 // DeepgramResponse represents the API response structure
 type DeepgramResponse struct {
 	Results struct {
 		Channels []struct {
-			Alternatives []struct {
-				Transcript string `json:"transcript"`
-			} `json:"alternatives"`
+			Alternatives []DeepgramAlternative `json:"alternatives"`
 		} `json:"channels"`
 	} `json:"results"`
 }
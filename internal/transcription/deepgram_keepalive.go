@@ -0,0 +1,66 @@
+package transcription
+
+import (
+	"log/slog"
+	"mac-dictation/internal/audio"
+	"time"
+)
+
+// keepAliveIdle is how long the stream goes without voice, or since the last
+// KeepAlive, before one is sent. Checked every keepAliveTick, so one goes out
+// within 5 seconds, well inside Deepgram's 10 second idle timeout.
+var (
+	keepAliveTick = time.Second
+	keepAliveIdle = 4 * time.Second
+)
+
+// keepAliveSilenceRMS is the level below which a chunk counts as silence
+const keepAliveSilenceRMS = 0.01
+
+// startKeepAlive sends KeepAlive messages while the recorder is delivering
+// silence or no audio at all, so Deepgram does not close the stream during
+// long pauses. Stopped by closing stop.
+//
+// https://developers.deepgram.com/docs/audio-keep-alive
+func (s *deepgramSession) startKeepAlive(stop <-chan struct{}) {
+	s.mu.Lock()
+	s.lastVoice = time.Now()
+	s.mu.Unlock()
+
+	tick, idleAfter := keepAliveTick, keepAliveIdle
+	go func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+
+		var lastSent time.Time
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-s.ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.mu.Lock()
+				idle := now.Sub(s.lastVoice) >= idleAfter && now.Sub(lastSent) >= idleAfter
+				conn, reconnecting := s.conn, s.reconnecting
+				s.mu.Unlock()
+
+				if !idle || conn == nil || reconnecting {
+					continue
+				}
+				if err := s.sendMessage(conn, KeepAlive); err != nil {
+					slog.Warn("Failed to send Deepgram KeepAlive", "error", err)
+				}
+				lastSent = now
+			}
+		}
+	}()
+}
+
+// trackVoice records when the last non-silent chunk was sent. Must be called with mu held.
+func (s *deepgramSession) trackVoice(data []byte) {
+	if rms, _ := audio.Levels(data); rms >= keepAliveSilenceRMS {
+		s.lastVoice = time.Now()
+	}
+}
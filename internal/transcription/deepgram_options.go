@@ -0,0 +1,122 @@
+package transcription
+
+import (
+	"fmt"
+	"mac-dictation/internal/audio"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Deepgram listen API endpoints, replaced by a local server in tests
+var (
+	deepgramStreamEndpoint = "wss://api.deepgram.com/v1/listen"
+	deepgramBatchEndpoint  = "https://api.deepgram.com/v1/listen"
+)
+
+// MaxKeyterms caps the vocabulary sent with each request. Deepgram limits the
+// size of keyterm prompts, and a long URL is rejected before it reaches the API.
+const MaxKeyterms = 100
+
+// DeepgramOptions configures the Deepgram listen API for both streaming and batch requests
+//
+// https://developers.deepgram.com/reference/speech-to-text-api/listen-streaming
+type DeepgramOptions struct {
+	Language string
+	Model    string
+	// SmartFormat formats dates, numbers and the like. Nil keeps each
+	// endpoint's default, off for streaming and on for batch requests.
+	SmartFormat     *bool
+	Numerals        bool
+	ProfanityFilter bool
+	// Endpointing is the silence in milliseconds before speech is finalised,
+	// 0 leaves Deepgram's default. Streaming only.
+	Endpointing int
+	// UtteranceEndMs is the gap in milliseconds between words before an
+	// UtteranceEnd message is sent, 0 disables it. Streaming only.
+	UtteranceEndMs int
+	// Diarize labels each word with the speaker who said it
+	Diarize bool
+	// Keyterms are words and phrases to boost recognition of. Sent as keyterm
+	// for Nova-3 models and keywords for older models, up to MaxKeyterms.
+	Keyterms []string
+}
+
+func DefaultDeepgramOptions() DeepgramOptions {
+	return DeepgramOptions{
+		Language:       "en-GB",
+		Model:          "nova-3",
+		UtteranceEndMs: 5000,
+	}
+}
+
+func (o DeepgramOptions) Validate() error {
+	if o.Language == "" {
+		return fmt.Errorf("deepgram language is required")
+	}
+	if o.Model == "" {
+		return fmt.Errorf("deepgram model is required")
+	}
+	if o.Endpointing < 0 {
+		return fmt.Errorf("deepgram endpointing must not be negative, got %d", o.Endpointing)
+	}
+	// Deepgram rejects utterance_end_ms values below 1000
+	if o.UtteranceEndMs != 0 && o.UtteranceEndMs < 1000 {
+		return fmt.Errorf("deepgram utterance end must be at least 1000ms, got %d", o.UtteranceEndMs)
+	}
+	return nil
+}
+
+// query builds the parameters shared by the streaming and batch endpoints
+func (o DeepgramOptions) query() url.Values {
+	q := url.Values{}
+	q.Set("model", o.Model)
+	q.Set("language", o.Language)
+	q.Set("encoding", "linear16")
+	q.Set("sample_rate", strconv.Itoa(audio.SampleRate))
+	q.Set("channels", strconv.Itoa(audio.Channels))
+	q.Set("punctuate", "true")
+	q.Set("numerals", strconv.FormatBool(o.Numerals))
+	q.Set("profanity_filter", strconv.FormatBool(o.ProfanityFilter))
+	q.Set("diarize", strconv.FormatBool(o.Diarize))
+
+	param := "keywords"
+	if strings.HasPrefix(o.Model, "nova-3") {
+		param = "keyterm"
+	}
+	terms := o.Keyterms
+	if len(terms) > MaxKeyterms {
+		terms = terms[:MaxKeyterms]
+	}
+	for _, term := range terms {
+		q.Add(param, term)
+	}
+	return q
+}
+
+func (o DeepgramOptions) streamURL() string {
+	q := o.query()
+	q.Set("smart_format", strconv.FormatBool(o.smartFormat(false)))
+	q.Set("interim_results", "true")
+	if o.Endpointing > 0 {
+		q.Set("endpointing", strconv.Itoa(o.Endpointing))
+	}
+	if o.UtteranceEndMs > 0 {
+		q.Set("utterance_end_ms", strconv.Itoa(o.UtteranceEndMs))
+	}
+	return deepgramStreamEndpoint + "?" + q.Encode()
+}
+
+func (o DeepgramOptions) batchURL() string {
+	q := o.query()
+	q.Set("smart_format", strconv.FormatBool(o.smartFormat(true)))
+	return deepgramBatchEndpoint + "?" + q.Encode()
+}
+
+// smartFormat returns the smart_format setting, or fallback when it is unset
+func (o DeepgramOptions) smartFormat(fallback bool) bool {
+	if o.SmartFormat == nil {
+		return fallback
+	}
+	return *o.SmartFormat
+}
@@ -0,0 +1,59 @@
+package transcription
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestDeepgramOptionsQuery(t *testing.T) {
+	opts := DefaultDeepgramOptions()
+	for i := range MaxKeyterms + 20 {
+		opts.Keyterms = append(opts.Keyterms, fmt.Sprintf("term%d", i))
+	}
+
+	q := opts.query()
+	if got := len(q["keyterm"]); got != MaxKeyterms {
+		t.Errorf("sent %d keyterms, want %d", got, MaxKeyterms)
+	}
+	if got := q["keyterm"][0]; got != "term0" {
+		t.Errorf("first keyterm %q, want term0", got)
+	}
+
+	opts.Model = "nova-2"
+	q = opts.query()
+	if len(q["keyterm"]) != 0 || len(q["keywords"]) != MaxKeyterms {
+		t.Errorf("nova-2 sent %d keyterms and %d keywords, want keywords only", len(q["keyterm"]), len(q["keywords"]))
+	}
+}
+
+func TestDeepgramOptionsSmartFormat(t *testing.T) {
+	smartFormat := func(rawURL string) string {
+		t.Helper()
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", rawURL, err)
+		}
+		return u.Query().Get("smart_format")
+	}
+
+	opts := DefaultDeepgramOptions()
+	if got := smartFormat(opts.streamURL()); got != "false" {
+		t.Errorf("streaming smart_format %q by default, want false", got)
+	}
+	if got := smartFormat(opts.batchURL()); got != "true" {
+		t.Errorf("batch smart_format %q by default, want true", got)
+	}
+
+	enabled := true
+	opts.SmartFormat = &enabled
+	if got := smartFormat(opts.streamURL()); got != "true" {
+		t.Errorf("streaming smart_format %q when enabled, want true", got)
+	}
+
+	disabled := false
+	opts.SmartFormat = &disabled
+	if got := smartFormat(opts.batchURL()); got != "false" {
+		t.Errorf("batch smart_format %q when disabled, want false", got)
+	}
+}
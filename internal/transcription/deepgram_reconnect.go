@@ -0,0 +1,162 @@
+package transcription
+
+import (
+	"fmt"
+	"log/slog"
+	"mac-dictation/internal/audio"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxReconnectAttempts    = 5
+	reconnectInitialBackoff = 500 * time.Millisecond
+	reconnectMaxBackoff     = 8 * time.Second
+	// maxUnackedBytes bounds the audio held for replay if Deepgram stops finalising results
+	maxUnackedBytes = 2 * 60 * audio.BytesPerSecond
+	// replayChunkBytes is the size of each message when replaying buffered audio
+	replayChunkBytes = audio.BytesPerSecond / 10
+)
+
+type ReconnectStatus string
+
+const (
+	ReconnectStatusReconnecting ReconnectStatus = "reconnecting"
+	ReconnectStatusReconnected  ReconnectStatus = "reconnected"
+	ReconnectStatusFailed       ReconnectStatus = "failed"
+)
+
+// ReconnectEvent reports progress recovering a dropped stream
+type ReconnectEvent struct {
+	Status  ReconnectStatus `json:"status"`
+	Attempt int             `json:"attempt"`
+	// ReplayedSecs is the unacknowledged audio resent on the new connection
+	ReplayedSecs float64 `json:"replayedSecs,omitempty"`
+}
+
+// Reconnector is implemented by streaming sessions that recover from dropped connections
+type Reconnector interface {
+	// Reconnects delivers reconnect progress, and is closed when the session ends
+	Reconnects() <-chan ReconnectEvent
+}
+
+var _ Reconnector = &deepgramSession{}
+
+func (s *deepgramSession) Reconnects() <-chan ReconnectEvent {
+	return s.reconnects
+}
+
+func (s *deepgramSession) emitReconnect(event ReconnectEvent) {
+	select {
+	case s.reconnects <- event:
+	default:
+	}
+}
+
+// buffer holds sent audio until it is acknowledged. Must be called with mu held.
+func (s *deepgramSession) buffer(data []byte) {
+	s.unacked = append(s.unacked, data...)
+
+	// Trimming is skipped while reconnecting as resume is reading the buffer
+	if !s.reconnecting && len(s.unacked) > maxUnackedBytes {
+		s.drop(len(s.unacked) - maxUnackedBytes)
+	}
+}
+
+// acknowledge drops buffered audio covered by a final result ending at end
+// seconds into the current connection. Must be called with mu held.
+func (s *deepgramSession) acknowledge(end float64) {
+	n := int((s.offset + end - s.acked) * audio.BytesPerSecond)
+	s.drop(n)
+}
+
+// drop discards n bytes from the front of the buffer. Must be called with mu held.
+func (s *deepgramSession) drop(n int) {
+	n -= n % audio.BytesPerSample
+	n = max(0, min(n, len(s.unacked)))
+	s.unacked = s.unacked[n:]
+	s.acked += float64(n) / audio.BytesPerSecond
+}
+
+// reconnect dials a new connection with exponential backoff and replays unacknowledged audio
+func (s *deepgramSession) reconnect(cause error) (*websocket.Conn, error) {
+	s.mu.Lock()
+	s.conn = nil
+	s.reconnecting = true
+	s.mu.Unlock()
+
+	backoff := reconnectInitialBackoff
+	for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+		s.emitReconnect(ReconnectEvent{Status: ReconnectStatusReconnecting, Attempt: attempt})
+		select {
+		case <-time.After(backoff):
+		case <-s.ctx.Done():
+			return nil, s.ctx.Err()
+		}
+		backoff = min(backoff*2, reconnectMaxBackoff)
+
+		conn, err := s.dial()
+		if err != nil {
+			slog.Warn("Deepgram reconnect failed", "error", err, "attempt", attempt)
+			continue
+		}
+
+		replayed, err := s.resume(conn)
+		if err != nil {
+			slog.Warn("Failed to replay audio to Deepgram", "error", err, "attempt", attempt)
+			_ = conn.Close()
+			continue
+		}
+
+		slog.Info("Reconnected to Deepgram", "attempt", attempt, "replayedBytes", replayed)
+		s.emitReconnect(ReconnectEvent{
+			Status:       ReconnectStatusReconnected,
+			Attempt:      attempt,
+			ReplayedSecs: float64(replayed) / audio.BytesPerSecond,
+		})
+		return conn, nil
+	}
+
+	s.mu.Lock()
+	s.reconnecting = false
+	s.mu.Unlock()
+
+	s.emitReconnect(ReconnectEvent{Status: ReconnectStatusFailed, Attempt: maxReconnectAttempts})
+	return nil, fmt.Errorf("failed to reconnect to Deepgram after %d attempts: %w", maxReconnectAttempts, cause)
+}
+
+// resume replays unacknowledged audio on conn, including audio that arrives
+// during the replay, then makes conn the active connection
+func (s *deepgramSession) resume(conn *websocket.Conn) (int, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	// Timestamps on the new connection restart from zero at the first replayed byte
+	s.mu.Lock()
+	s.offset = s.acked
+	s.mu.Unlock()
+
+	sent := 0
+	for {
+		s.mu.Lock()
+		if sent >= len(s.unacked) {
+			s.conn = conn
+			s.reconnecting = false
+			closing := s.closing
+			s.mu.Unlock()
+
+			if closing {
+				return sent, conn.WriteJSON(Message{string(CloseStream)})
+			}
+			return sent, nil
+		}
+		chunk := s.unacked[sent:min(sent+replayChunkBytes, len(s.unacked))]
+		s.mu.Unlock()
+
+		if err := conn.WriteMessage(websocket.BinaryMessage, chunk); err != nil {
+			return sent, err
+		}
+		sent += len(chunk)
+	}
+}
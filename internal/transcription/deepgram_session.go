@@ -0,0 +1,297 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// deepgramSession is a single Deepgram websocket stream, including any
+// reconnections made while recovering from a dropped connection
+type deepgramSession struct {
+	apiKey  string
+	options DeepgramOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	conn       *websocket.Conn
+	done       chan struct{}
+	err        chan error
+	results    chan StreamResult
+	reconnects chan ReconnectEvent
+
+	// writeMu serialises writes to the websocket, which does not support concurrent writers
+	writeMu sync.Mutex
+
+	mu         sync.Mutex
+	transcript strings.Builder
+	words      []Word
+	// unacked is audio sent since the last final result, replayed after a reconnect
+	unacked []byte
+	// acked is the seconds of audio covered by final results
+	acked float64
+	// offset is the seconds of audio sent on previous connections, added to word timings
+	offset       float64
+	closing      bool
+	reconnecting bool
+	// lastVoice is when the last non-silent chunk was sent, used to decide when to send KeepAlive
+	lastVoice     time.Time
+	stopKeepAlive chan struct{}
+}
+
+var _ Session = &deepgramSession{}
+
+func newDeepgramSession(ctx context.Context, apiKey string, options DeepgramOptions) *deepgramSession {
+	ctx, cancel := context.WithCancel(ctx)
+	return &deepgramSession{
+		apiKey:        apiKey,
+		options:       options,
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+		err:           make(chan error, 1),
+		results:       make(chan StreamResult, resultBufferSize),
+		reconnects:    make(chan ReconnectEvent, resultBufferSize),
+		stopKeepAlive: make(chan struct{}),
+	}
+}
+
+func (s *deepgramSession) start() error {
+	c, err := s.dial()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.conn = c
+	s.mu.Unlock()
+
+	go s.run(c)
+	go s.closeOnCancel()
+	s.startKeepAlive(s.stopKeepAlive)
+
+	return nil
+}
+
+func (s *deepgramSession) dial() (*websocket.Conn, error) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Token "+s.apiKey)
+
+	c, _, err := websocket.DefaultDialer.DialContext(s.ctx, s.options.streamURL(), headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Deepgram API: %w", err)
+	}
+	return c, nil
+}
+
+// closeOnCancel closes the connection when the session is cancelled, unblocking any pending read or write
+func (s *deepgramSession) closeOnCancel() {
+	select {
+	case <-s.ctx.Done():
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+		if conn != nil {
+			_ = conn.Close()
+		}
+	case <-s.done:
+	}
+}
+
+// run reads results until the stream is closed, reconnecting if the connection drops
+func (s *deepgramSession) run(c *websocket.Conn) {
+	defer func() {
+		close(s.results)
+		close(s.reconnects)
+		close(s.done)
+	}()
+
+	for {
+		err := s.readLoop(c)
+		_ = c.Close()
+
+		if s.ctx.Err() != nil {
+			s.err <- s.ctx.Err()
+			return
+		}
+
+		s.mu.Lock()
+		closing, pending := s.closing, len(s.unacked)
+		s.mu.Unlock()
+
+		var closeErr *websocket.CloseError
+		normalClose := errors.As(err, &closeErr) && closeErr.Code == websocket.CloseNormalClosure
+		if closing && (normalClose || pending == 0) {
+			return
+		}
+
+		slog.Error("Failed to read message", "error", err)
+		c, err = s.reconnect(err)
+		if err != nil {
+			s.err <- err
+			return
+		}
+	}
+}
+
+// readLoop handles messages from a single connection and returns the error that ended it
+func (s *deepgramSession) readLoop(c *websocket.Conn) error {
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		slog.Debug("Received raw message", "message", string(message))
+
+		var msg Message
+		if err := json.Unmarshal(message, &msg); err != nil {
+			slog.Error("Failed to unmarshal message", "error", err)
+			continue
+		}
+
+		switch msg.Type {
+		case string(Results):
+			var result DeepgramStreamingResponse
+			if err := json.Unmarshal(message, &result); err != nil {
+				slog.Error("Failed to unmarshal result message", "error", err)
+				continue
+			}
+
+			s.mu.Lock()
+			endSecs := s.offset + result.Start + result.Duration
+			if result.IsFinal {
+				s.acknowledge(result.Start + result.Duration)
+			}
+			s.mu.Unlock()
+
+			if len(result.Channel.Alternatives) == 0 {
+				continue
+			}
+			alternative := result.Channel.Alternatives[0]
+			transcript := alternative.Transcript
+			if transcript != "" {
+				s.emitResult(StreamResult{Text: transcript, IsFinal: result.IsFinal, EndSecs: endSecs})
+			}
+
+			if result.IsFinal && transcript != "" {
+				s.mu.Lock()
+				if s.transcript.Len() > 0 {
+					s.transcript.WriteString(" ")
+				}
+				s.transcript.WriteString(transcript)
+				for _, word := range toWords(alternative.Words) {
+					word.Start += s.offset
+					word.End += s.offset
+					s.words = append(s.words, word)
+				}
+				s.mu.Unlock()
+			}
+		case string(UtteranceEnd):
+			s.mu.Lock()
+			s.transcript.WriteString("\n")
+			s.mu.Unlock()
+			// TODO: Should we close?
+		}
+	}
+}
+
+// emitResult delivers a result without blocking the read loop, dropping it if the consumer has fallen behind
+func (s *deepgramSession) emitResult(result StreamResult) {
+	select {
+	case s.results <- result:
+	default:
+		slog.Warn("Dropping Deepgram result, consumer is not keeping up", "isFinal", result.IsFinal)
+	}
+}
+
+func (s *deepgramSession) Results() <-chan StreamResult {
+	return s.results
+}
+
+// SendChunk sends audio to Deepgram. Audio is buffered until acknowledged by
+// a final result, and while reconnecting it is only buffered, to be replayed
+// once the new connection is up.
+func (s *deepgramSession) SendChunk(data []byte) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.conn == nil && !s.reconnecting {
+		s.mu.Unlock()
+		return fmt.Errorf("connection not started")
+	}
+	s.buffer(data)
+	s.trackVoice(data)
+	conn, reconnecting := s.conn, s.reconnecting
+	s.mu.Unlock()
+
+	if reconnecting {
+		return nil
+	}
+
+	s.writeMu.Lock()
+	err := conn.WriteMessage(websocket.BinaryMessage, data)
+	s.writeMu.Unlock()
+	if err != nil {
+		// Closing the connection ends the read loop, which reconnects and replays the chunk
+		slog.Warn("Failed to send audio to Deepgram, reconnecting", "error", err)
+		_ = conn.Close()
+	}
+	return nil
+}
+
+func (s *deepgramSession) EndStream() (Result, error) {
+	defer s.cancel()
+
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		return Result{}, fmt.Errorf("stream already ended")
+	}
+	s.closing = true
+	conn, reconnecting := s.conn, s.reconnecting
+	close(s.stopKeepAlive)
+	s.mu.Unlock()
+
+	// A reconnect in progress sends CloseStream itself once the audio is replayed
+	if conn != nil && !reconnecting {
+		if err := s.sendMessage(conn, CloseStream); err != nil {
+			slog.Warn("Failed to close Deepgram stream, reconnecting", "error", err)
+			_ = conn.Close()
+		}
+	}
+
+	<-s.done
+
+	select {
+	case err := <-s.err:
+		return Result{}, err
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Result{Text: s.transcript.String(), Words: s.words}, nil
+}
+
+// Cancel aborts the session, closing the connection without waiting for final results
+func (s *deepgramSession) Cancel() {
+	s.cancel()
+}
+
+func (s *deepgramSession) sendMessage(conn *websocket.Conn, messageType MessageType) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return conn.WriteJSON(Message{string(messageType)})
+}
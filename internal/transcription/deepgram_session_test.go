@@ -0,0 +1,320 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mac-dictation/internal/audio"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeDeepgram is a local websocket server standing in for the Deepgram
+// streaming API. handle is called with each connection and its index.
+type fakeDeepgram struct {
+	mu       sync.Mutex
+	conns    int
+	audio    [][]byte
+	messages []string
+}
+
+func newFakeDeepgram(t *testing.T, handle func(f *fakeDeepgram, index int, c *websocket.Conn)) *fakeDeepgram {
+	t.Helper()
+
+	f := &fakeDeepgram{}
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade connection: %v", err)
+			return
+		}
+		defer c.Close()
+
+		f.mu.Lock()
+		index := f.conns
+		f.conns++
+		f.audio = append(f.audio, nil)
+		f.mu.Unlock()
+
+		handle(f, index, c)
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint := deepgramStreamEndpoint
+	deepgramStreamEndpoint = "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/listen"
+	t.Cleanup(func() { deepgramStreamEndpoint = endpoint })
+	return f
+}
+
+// read receives the next message, recording audio and control messages.
+// It returns the control message type, or "" for audio.
+func (f *fakeDeepgram) read(index int, c *websocket.Conn) (string, error) {
+	kind, data, err := c.ReadMessage()
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if kind == websocket.BinaryMessage {
+		f.audio[index] = append(f.audio[index], data...)
+		return "", nil
+	}
+
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return "", err
+	}
+	f.messages = append(f.messages, msg.Type)
+	return msg.Type, nil
+}
+
+func (f *fakeDeepgram) received(index int) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if index >= len(f.audio) {
+		return nil
+	}
+	return append([]byte(nil), f.audio[index]...)
+}
+
+func (f *fakeDeepgram) count(messageType MessageType) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, msg := range f.messages {
+		if msg == string(messageType) {
+			n++
+		}
+	}
+	return n
+}
+
+// finalResult writes a final Results message covering start to start+duration seconds
+func finalResult(c *websocket.Conn, transcript string, start, duration float64) error {
+	var result DeepgramStreamingResponse
+	result.Type = string(Results)
+	result.IsFinal = true
+	result.Start = start
+	result.Duration = duration
+	result.Channel.Alternatives = []DeepgramAlternative{{Transcript: transcript}}
+	return c.WriteJSON(result)
+}
+
+// closeAfterResult answers CloseStream with a final result, then closes normally
+func closeAfterResult(c *websocket.Conn, transcript string) {
+	_ = finalResult(c, transcript, 0, 0.1)
+	_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+// tone returns secs of audio loud enough to count as voice
+func tone(secs float64) []byte {
+	samples := int(secs * audio.SampleRate)
+	buf := make([]byte, samples*audio.BytesPerSample)
+	for i := range samples {
+		sample := int16(8000)
+		if i%2 == 1 {
+			sample = -sample
+		}
+		binary.LittleEndian.PutUint16(buf[i*audio.BytesPerSample:], uint16(sample))
+	}
+	return buf
+}
+
+func setKeepAlive(t *testing.T, tick, idle time.Duration) {
+	t.Helper()
+	prevTick, prevIdle := keepAliveTick, keepAliveIdle
+	keepAliveTick, keepAliveIdle = tick, idle
+	t.Cleanup(func() { keepAliveTick, keepAliveIdle = prevTick, prevIdle })
+}
+
+func startTestSession(t *testing.T) *deepgramSession {
+	t.Helper()
+	session := newDeepgramSession(context.Background(), "test-key", DefaultDeepgramOptions())
+	if err := session.start(); err != nil {
+		t.Fatalf("failed to start session: %v", err)
+	}
+	t.Cleanup(session.Cancel)
+	return session
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDeepgramKeepAlive(t *testing.T) {
+	setKeepAlive(t, 10*time.Millisecond, 100*time.Millisecond)
+
+	fake := newFakeDeepgram(t, func(f *fakeDeepgram, index int, c *websocket.Conn) {
+		for {
+			msg, err := f.read(index, c)
+			if err != nil {
+				return
+			}
+			if msg == string(CloseStream) {
+				closeAfterResult(c, "done")
+				return
+			}
+		}
+	})
+	session := startTestSession(t)
+
+	// Voice keeps the stream busy, so no KeepAlive is needed
+	for range 30 {
+		if err := session.SendChunk(tone(0.01)); err != nil {
+			t.Fatalf("failed to send chunk: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := fake.count(KeepAlive); n != 0 {
+		t.Fatalf("sent %d KeepAlive messages while voice was sent", n)
+	}
+
+	// Silence does not count as voice
+	if err := session.SendChunk(make([]byte, audio.BytesPerSecond/10)); err != nil {
+		t.Fatalf("failed to send chunk: %v", err)
+	}
+	waitFor(t, "KeepAlive", func() bool { return fake.count(KeepAlive) >= 2 })
+
+	// KeepAlive is repeated every keepAliveIdle rather than every tick
+	time.Sleep(150 * time.Millisecond)
+	if n := fake.count(KeepAlive); n > 5 {
+		t.Fatalf("sent %d KeepAlive messages, want them spaced by the idle interval", n)
+	}
+
+	result, err := session.EndStream()
+	if err != nil {
+		t.Fatalf("failed to end stream: %v", err)
+	}
+	if result.Text != "done" {
+		t.Fatalf("transcript %q, want %q", result.Text, "done")
+	}
+}
+
+func TestDeepgramReconnectReplaysUnacknowledgedAudio(t *testing.T) {
+	first := tone(0.1)
+	second := tone(0.2)
+	third := tone(0.3)
+
+	fake := newFakeDeepgram(t, func(f *fakeDeepgram, index int, c *websocket.Conn) {
+		if index == 0 {
+			// Acknowledge the first chunk, then drop the connection after the second
+			if _, err := f.read(index, c); err != nil {
+				return
+			}
+			_ = finalResult(c, "one", 0, 0.1)
+			if _, err := f.read(index, c); err != nil {
+				return
+			}
+			_ = c.UnderlyingConn().Close()
+			return
+		}
+
+		for {
+			msg, err := f.read(index, c)
+			if err != nil {
+				return
+			}
+			if msg == string(CloseStream) {
+				closeAfterResult(c, "two three")
+				return
+			}
+		}
+	})
+	session := startTestSession(t)
+
+	if err := session.SendChunk(first); err != nil {
+		t.Fatalf("failed to send chunk: %v", err)
+	}
+	waitFor(t, "first result", func() bool {
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		return session.transcript.Len() > 0
+	})
+	if err := session.SendChunk(second); err != nil {
+		t.Fatalf("failed to send chunk: %v", err)
+	}
+
+	var event ReconnectEvent
+	for event = range session.Reconnects() {
+		if event.Status != ReconnectStatusReconnecting {
+			break
+		}
+	}
+	if event.Status != ReconnectStatusReconnected {
+		t.Fatalf("reconnect status %q, want %q", event.Status, ReconnectStatusReconnected)
+	}
+
+	if err := session.SendChunk(third); err != nil {
+		t.Fatalf("failed to send chunk: %v", err)
+	}
+
+	result, err := session.EndStream()
+	if err != nil {
+		t.Fatalf("failed to end stream: %v", err)
+	}
+	if result.Text != "one two three" {
+		t.Fatalf("transcript %q, want %q", result.Text, "one two three")
+	}
+
+	// Only the audio after the acknowledged first chunk is replayed
+	want := append(append([]byte(nil), second...), third...)
+	if got := fake.received(1); !bytes.Equal(got, want) {
+		t.Fatalf("second connection received %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestDeepgramReconnectDuringEndStream(t *testing.T) {
+	chunk := tone(0.2)
+
+	fake := newFakeDeepgram(t, func(f *fakeDeepgram, index int, c *websocket.Conn) {
+		for {
+			msg, err := f.read(index, c)
+			if err != nil {
+				return
+			}
+			// The first connection drops instead of finalising
+			if msg == string(CloseStream) && index == 0 {
+				_ = c.UnderlyingConn().Close()
+				return
+			}
+			if msg == string(CloseStream) {
+				closeAfterResult(c, "recovered")
+				return
+			}
+		}
+	})
+	session := startTestSession(t)
+
+	if err := session.SendChunk(chunk); err != nil {
+		t.Fatalf("failed to send chunk: %v", err)
+	}
+	waitFor(t, "audio", func() bool { return len(fake.received(0)) == len(chunk) })
+
+	result, err := session.EndStream()
+	if err != nil {
+		t.Fatalf("failed to end stream: %v", err)
+	}
+	if result.Text != "recovered" {
+		t.Fatalf("transcript %q, want %q", result.Text, "recovered")
+	}
+	if got := fake.received(1); !bytes.Equal(got, chunk) {
+		t.Fatalf("second connection received %d bytes, want %d", len(got), len(chunk))
+	}
+}
@@ -0,0 +1,231 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mac-dictation/internal/audio"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	Whisper1            = "whisper-1"
+	Gpt4oTranscribe     = "gpt-4o-transcribe"
+	Gpt4oMiniTranscribe = "gpt-4o-mini-transcribe"
+)
+
+const (
+	OpenAiBaseURL = "https://api.openai.com/v1"
+	// DefaultLocalBaseURL is where a local whisper.cpp server listens by default.
+	// whisper.cpp must be started with --inference-path /v1/audio/transcriptions
+	// to expose the OpenAI compatible endpoint.
+	DefaultLocalBaseURL = "http://127.0.0.1:8080/v1"
+)
+
+// OpenAiTranscriptionService transcribes audio with the OpenAI audio transcriptions API,
+// or any self-hosted server exposing an OpenAI compatible /audio/transcriptions endpoint.
+//
+// The API has no streaming input, so sessions buffer audio in SendChunk and
+// upload it as a single WAV file in EndStream. No interim results are produced.
+type OpenAiTranscriptionService struct {
+	apiKey  string
+	baseURL string
+	model   string
+	// requiresKey is false for self-hosted servers which accept unauthenticated requests
+	requiresKey bool
+}
+
+// openAiTranscriptionSession buffers the audio of a single stream
+type openAiTranscriptionSession struct {
+	service *OpenAiTranscriptionService
+	ctx     context.Context
+	cancel  context.CancelFunc
+	results chan StreamResult
+
+	mu     sync.Mutex
+	ended  bool
+	buffer []byte
+}
+
+var _ Provider = &OpenAiTranscriptionService{}
+var _ BatchOnly = &OpenAiTranscriptionService{}
+var _ Session = &openAiTranscriptionSession{}
+
+func NewOpenAiTranscriptionService(apiKey, model string) *OpenAiTranscriptionService {
+	if model == "" {
+		model = Gpt4oTranscribe
+	}
+	return &OpenAiTranscriptionService{apiKey: apiKey, baseURL: OpenAiBaseURL, model: model, requiresKey: true}
+}
+
+// NewLocalTranscriptionService creates a provider for a self-hosted OpenAI compatible
+// server such as whisper.cpp. No API key is required.
+func NewLocalTranscriptionService(baseURL, model string) *OpenAiTranscriptionService {
+	if baseURL == "" {
+		baseURL = DefaultLocalBaseURL
+	}
+	if model == "" {
+		model = Whisper1
+	}
+	return &OpenAiTranscriptionService{baseURL: strings.TrimSuffix(baseURL, "/"), model: model}
+}
+
+func (s *OpenAiTranscriptionService) BatchOnly() {}
+
+func (s *OpenAiTranscriptionService) StartStream(ctx context.Context) (Session, error) {
+	if s.requiresKey && s.apiKey == "" {
+		return nil, fmt.Errorf("missing openai API Key")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &openAiTranscriptionSession{
+		service: s,
+		ctx:     ctx,
+		cancel:  cancel,
+		results: make(chan StreamResult),
+	}, nil
+}
+
+func (s *openAiTranscriptionSession) SendChunk(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return fmt.Errorf("stream already ended")
+	}
+	s.buffer = append(s.buffer, data...)
+	return nil
+}
+
+// Results is closed when the session ends, as the batch API does not produce interim results
+func (s *openAiTranscriptionSession) Results() <-chan StreamResult {
+	return s.results
+}
+
+func (s *openAiTranscriptionSession) EndStream() (Result, error) {
+	defer s.cancel()
+
+	audioData, err := s.end()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(audioData) == 0 {
+		return Result{}, nil
+	}
+	return s.service.Transcribe(s.ctx, audioData)
+}
+
+// Cancel discards the buffered audio and aborts an upload in progress
+func (s *openAiTranscriptionSession) Cancel() {
+	_, _ = s.end()
+	s.cancel()
+}
+
+func (s *openAiTranscriptionSession) end() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return nil, fmt.Errorf("stream already ended")
+	}
+	s.ended = true
+	close(s.results)
+
+	audioData := s.buffer
+	s.buffer = nil
+	return audioData, nil
+}
+
+type openAiTranscriptionResponse struct {
+	Text string `json:"text"`
+}
+
+// Transcribe uploads PCM16 audio as WAV to the transcriptions API
+//
+// https://platform.openai.com/docs/api-reference/audio/createTranscription
+func (s *OpenAiTranscriptionService) Transcribe(ctx context.Context, audioData []byte) (Result, error) {
+	if s.requiresKey && s.apiKey == "" {
+		return Result{}, fmt.Errorf("missing openai API Key")
+	}
+
+	body, contentType, err := transcriptionRequestBody(audioData, s.model)
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	return doTranscriptionRequest(req)
+}
+
+// transcriptionRequestBody builds the multipart form for an OpenAI compatible transcriptions endpoint
+func transcriptionRequestBody(audioData []byte, model string) (io.Reader, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", "audio.wav")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(audio.EncodeWAV(audioData)); err != nil {
+		return nil, "", fmt.Errorf("failed to write audio: %w", err)
+	}
+
+	if err := writer.WriteField("model", model); err != nil {
+		return nil, "", fmt.Errorf("failed to write model field: %w", err)
+	}
+	if err := writer.WriteField("response_format", "json"); err != nil {
+		return nil, "", fmt.Errorf("failed to write response format field: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	return &body, writer.FormDataContentType(), nil
+}
+
+func doTranscriptionRequest(req *http.Request) (Result, error) {
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			slog.Error("failed to close response body", "error", err)
+		}
+	}(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result openAiTranscriptionResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return Result{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return Result{Text: result.Text}, nil
+}
@@ -0,0 +1,66 @@
+package transcription
+
+import "context"
+
+// Provider creates streaming sessions and transcribes recorded audio
+type Provider interface {
+	// StartStream opens a new streaming session. Cancelling ctx aborts the
+	// session, including any in-flight network I/O.
+	StartStream(ctx context.Context) (Session, error)
+
+	// Transcribe sends audio to the provider API and returns the transcription synchronously
+	Transcribe(ctx context.Context, audioData []byte) (Result, error)
+}
+
+// BatchOnly is implemented by providers without a streaming API. Their sessions
+// buffer the audio and transcribe it in a single request when the stream ends,
+// so transcribing the same audio again is no fallback.
+type BatchOnly interface {
+	BatchOnly()
+}
+
+// IsBatchOnly reports whether the provider's sessions are a single batch request
+func IsBatchOnly(provider Provider) bool {
+	_, ok := provider.(BatchOnly)
+	return ok
+}
+
+// Session is a single streaming transcription. Each session owns its
+// connection and transcript, so overlapping sessions never share state.
+type Session interface {
+	SendChunk(data []byte) error
+	// Results delivers interim and final transcripts, and is closed when the session ends
+	Results() <-chan StreamResult
+	// EndStream flushes the remaining audio and returns the complete transcript
+	EndStream() (Result, error)
+	// Cancel aborts the session without waiting for a transcript
+	Cancel()
+}
+
+// StreamResult is an interim or final transcript received during a session.
+// EndSecs is how far into the session's audio the transcript reaches, 0 if unknown.
+type StreamResult struct {
+	Text    string
+	IsFinal bool
+	EndSecs float64
+}
+
+// resultBufferSize is how many results a session holds for a slow consumer before dropping interim results
+const resultBufferSize = 64
+
+// Result is a completed transcription. Words is only populated by providers
+// that return word level timings.
+type Result struct {
+	Text  string
+	Words []Word
+}
+
+// Word is a recognised word, with start and end in seconds from the start of the audio
+type Word struct {
+	Word       string  `json:"word"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Confidence float64 `json:"confidence"`
+	// Speaker is only set when diarization is enabled
+	Speaker *int `json:"speaker,omitempty"`
+}
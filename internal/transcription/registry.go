@@ -0,0 +1,58 @@
+package transcription
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+const (
+	ProviderDeepgram = "deepgram"
+	ProviderOpenAI   = "openai"
+	ProviderLocal    = "local"
+)
+
+// Factory builds a Provider, typically from the current application settings
+type Factory func() Provider
+
+// Registry holds the available Provider implementations keyed by name
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds a provider factory, replacing any existing factory with the same name
+func (r *Registry) Register(name string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = factory
+}
+
+// New builds the provider registered under name
+func (r *Registry) New(name string) (Provider, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown transcription provider %q", name)
+	}
+	return factory(), nil
+}
+
+// Names returns the registered provider names in sorted order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
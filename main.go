@@ -45,7 +45,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	appService := NewApp(db)
+	dataDir, err := database.GetDataDirectory()
+	if err != nil {
+		slog.Error("failed to get data directory", "error", err)
+		os.Exit(1)
+	}
+
+	appService := NewApp(db, dataDir)
+
+	// Recordings interrupted by a crash are offered for recovery by the frontend
+	if orphaned := appService.DetectOrphanedJournals(); len(orphaned) > 0 {
+		slog.Info("unfinished recordings can be recovered", "count", len(orphaned))
+	}
 
 	app := application.New(application.Options{
 		Name:        "Mac Dictation",
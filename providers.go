@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"mac-dictation/internal/llm"
+	"mac-dictation/internal/storage"
+	"mac-dictation/internal/transcription"
+	"strconv"
+)
+
+// newProviderRegistry registers every transcription provider, each built from the current settings
+func newProviderRegistry(settings *storage.SettingsService, vocabulary *storage.VocabularyService) *transcription.Registry {
+	registry := transcription.NewRegistry()
+
+	registry.Register(transcription.ProviderDeepgram, func() transcription.Provider {
+		apiKey, _ := settings.Get(SettingDeepgramAPIKey)
+
+		opts := deepgramOptions(settings)
+		terms, err := vocabulary.Terms()
+		if err != nil {
+			slog.Error("failed to load vocabulary for deepgram", "error", err)
+		}
+		if len(terms) > transcription.MaxKeyterms {
+			slog.Warn("vocabulary exceeds the deepgram keyterm limit, only the first terms are sent",
+				"terms", len(terms), "limit", transcription.MaxKeyterms)
+		}
+		opts.Keyterms = terms
+
+		return transcription.NewDeepgramService(apiKey, opts)
+	})
+
+	registry.Register(transcription.ProviderOpenAI, func() transcription.Provider {
+		apiKey, _ := settings.Get(SettingOpenAIAPIKey)
+		model, _ := settings.Get(SettingOpenAITranscriptionModel)
+		return transcription.NewOpenAiTranscriptionService(apiKey, model)
+	})
+
+	registry.Register(transcription.ProviderLocal, func() transcription.Provider {
+		baseURL, _ := settings.Get(SettingLocalTranscriptionURL)
+		model, _ := settings.Get(SettingLocalTranscriptionModel)
+		return transcription.NewLocalTranscriptionService(baseURL, model)
+	})
+
+	return registry
+}
+
+// llmAPIKeySettings maps LLM providers to the setting holding their API key
+var llmAPIKeySettings = map[string]string{
+	llm.ProviderOpenAI:           SettingOpenAIAPIKey,
+	llm.ProviderAnthropic:        SettingAnthropicAPIKey,
+	llm.ProviderOpenAICompatible: SettingLLMAPIKey,
+}
+
+// newLLM builds the LLM selected in settings. An unknown provider is an error
+// rather than a fallback, so text is never sent to a provider the user did not choose.
+func newLLM(settings *storage.SettingsService) (llm.LLM, error) {
+	provider, _ := settings.Get(SettingLLMProvider)
+	if provider == "" {
+		provider = llm.ProviderOpenAI
+	}
+
+	config := llm.Config{}
+	config.APIKey, _ = settings.Get(llmAPIKeySettings[provider])
+	config.Model, _ = settings.Get(SettingLLMModel)
+	config.BaseURL, _ = settings.Get(SettingLLMBaseURL)
+
+	return llm.New(provider, config)
+}
+
+// providerAPIKeySettings maps transcription providers to the setting holding their API key.
+// Providers without an entry, such as local servers, do not need a key.
+var providerAPIKeySettings = map[string]string{
+	transcription.ProviderDeepgram: SettingDeepgramAPIKey,
+	transcription.ProviderOpenAI:   SettingOpenAIAPIKey,
+}
+
+// deepgramSettings are the settings used to build transcription.DeepgramOptions
+var deepgramSettings = []string{
+	SettingDeepgramLanguage,
+	SettingDeepgramModel,
+	SettingDeepgramSmartFormat,
+	SettingDeepgramNumerals,
+	SettingDeepgramProfanityFilter,
+	SettingDeepgramEndpointing,
+	SettingDeepgramUtteranceEndMs,
+	SettingDeepgramDiarize,
+}
+
+// deepgramOptions builds Deepgram options from settings. Unset settings keep their
+// defaults, and the defaults are used entirely if the result is invalid.
+func deepgramOptions(settings *storage.SettingsService) transcription.DeepgramOptions {
+	opts, err := loadDeepgramOptions(settings, "")
+	if err == nil {
+		err = opts.Validate()
+	}
+	if err != nil {
+		slog.Warn("invalid deepgram settings, using defaults", "error", err)
+		return transcription.DefaultDeepgramOptions()
+	}
+	return opts
+}
+
+// validateDeepgramSetting checks that changing key to value results in valid Deepgram options
+func validateDeepgramSetting(settings *storage.SettingsService, key, value string) error {
+	opts, err := loadDeepgramOptions(settings, key)
+	if err != nil {
+		return err
+	}
+	if value != "" {
+		if err := applyDeepgramSetting(&opts, key, value); err != nil {
+			return err
+		}
+	}
+	return opts.Validate()
+}
+
+// loadDeepgramOptions applies every saved Deepgram setting, except skipKey, over the defaults
+func loadDeepgramOptions(settings *storage.SettingsService, skipKey string) (transcription.DeepgramOptions, error) {
+	opts := transcription.DefaultDeepgramOptions()
+	for _, key := range deepgramSettings {
+		if key == skipKey {
+			continue
+		}
+
+		value, err := settings.Get(key)
+		if err != nil {
+			return opts, err
+		}
+		if value == "" {
+			continue
+		}
+
+		if err := applyDeepgramSetting(&opts, key, value); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+func applyDeepgramSetting(opts *transcription.DeepgramOptions, key, value string) error {
+	var err error
+	switch key {
+	case SettingDeepgramLanguage:
+		opts.Language = value
+	case SettingDeepgramModel:
+		opts.Model = value
+	case SettingDeepgramSmartFormat:
+		var smartFormat bool
+		smartFormat, err = strconv.ParseBool(value)
+		opts.SmartFormat = &smartFormat
+	case SettingDeepgramNumerals:
+		opts.Numerals, err = strconv.ParseBool(value)
+	case SettingDeepgramProfanityFilter:
+		opts.ProfanityFilter, err = strconv.ParseBool(value)
+	case SettingDeepgramEndpointing:
+		opts.Endpointing, err = strconv.Atoi(value)
+	case SettingDeepgramUtteranceEndMs:
+		opts.UtteranceEndMs, err = strconv.Atoi(value)
+	case SettingDeepgramDiarize:
+		opts.Diarize, err = strconv.ParseBool(value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// RecordingState is a stage in the recording lifecycle:
+// idle → starting → recording → finalizing → idle
+type RecordingState string
+
+const (
+	StateIdle       RecordingState = "idle"
+	StateStarting   RecordingState = "starting"
+	StateRecording  RecordingState = "recording"
+	StateFinalizing RecordingState = "finalizing"
+)
+
+// recordingTransitions lists the states reachable from each state.
+// A failed start returns straight to idle, and recovering a crashed recording
+// goes straight to finalizing.
+var recordingTransitions = map[RecordingState][]RecordingState{
+	StateIdle:       {StateStarting, StateFinalizing},
+	StateStarting:   {StateRecording, StateIdle},
+	StateRecording:  {StateFinalizing},
+	StateFinalizing: {StateIdle},
+}
+
+type StateChangedEvent struct {
+	State    RecordingState `json:"state"`
+	Previous RecordingState `json:"previous"`
+}
+
+// recordingStateMachine serialises recording lifecycle changes. Start, stop
+// and cancel can be triggered concurrently from the hotkey, tray and frontend,
+// so each claims its transition atomically and loses cleanly if another got there first.
+//
+// A rollover runs alongside the recording state rather than as a state of its
+// own. It is claimed the same way, and finalizing waits for it to finish.
+type recordingStateMachine struct {
+	mu       sync.Mutex
+	state    RecordingState
+	onChange func(event StateChangedEvent)
+
+	rollingOver bool
+	rollover    sync.WaitGroup
+}
+
+func newRecordingStateMachine(onChange func(event StateChangedEvent)) *recordingStateMachine {
+	return &recordingStateMachine{state: StateIdle, onChange: onChange}
+}
+
+func (m *recordingStateMachine) Current() RecordingState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// Transition moves from one state to another, failing if the machine is not
+// in from or the transition is not allowed
+func (m *recordingStateMachine) Transition(from, to RecordingState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != from {
+		return fmt.Errorf("cannot move from %s to %s, recording is %s", from, to, m.state)
+	}
+	if !slices.Contains(recordingTransitions[from], to) {
+		return fmt.Errorf("invalid recording transition from %s to %s", from, to)
+	}
+
+	slog.Debug("recording state changed", "from", from, "to", to)
+	m.state = to
+	// Notified under the lock so listeners see changes in order
+	if m.onChange != nil {
+		m.onChange(StateChangedEvent{State: to, Previous: from})
+	}
+	return nil
+}
+
+// BeginRollover claims the rollover of the current recording, failing if the
+// machine is not recording or a rollover is already running. A successful
+// claim must be released with EndRollover.
+func (m *recordingStateMachine) BeginRollover() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != StateRecording || m.rollingOver {
+		return false
+	}
+	m.rollingOver = true
+	m.rollover.Add(1)
+	return true
+}
+
+// EndRollover releases a claim taken by BeginRollover
+func (m *recordingStateMachine) EndRollover() {
+	m.mu.Lock()
+	m.rollingOver = false
+	m.mu.Unlock()
+	m.rollover.Done()
+}
+
+// WaitRollover blocks until the running rollover, if any, has finished. Once
+// the machine has left StateRecording no new rollover can be claimed, so a
+// caller that has moved it to finalizing waits for the last one.
+func (m *recordingStateMachine) WaitRollover() {
+	m.rollover.Wait()
+}
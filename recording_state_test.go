@@ -0,0 +1,185 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRecordingStateMachineTransitions(t *testing.T) {
+	m := newRecordingStateMachine(nil)
+
+	if err := m.Transition(StateIdle, StateRecording); err == nil {
+		t.Fatal("expected idle to recording to be rejected")
+	}
+	if err := m.Transition(StateRecording, StateFinalizing); err == nil {
+		t.Fatal("expected a transition from the wrong state to be rejected")
+	}
+
+	steps := [][2]RecordingState{
+		{StateIdle, StateStarting},
+		{StateStarting, StateRecording},
+		{StateRecording, StateFinalizing},
+		{StateFinalizing, StateIdle},
+		{StateIdle, StateStarting},
+		{StateStarting, StateIdle},
+	}
+	for _, step := range steps {
+		if err := m.Transition(step[0], step[1]); err != nil {
+			t.Fatalf("transition %s to %s: %v", step[0], step[1], err)
+		}
+	}
+	if got := m.Current(); got != StateIdle {
+		t.Fatalf("state %s, want %s", got, StateIdle)
+	}
+}
+
+func TestRecordingStateMachineRolloverClaim(t *testing.T) {
+	m := newRecordingStateMachine(nil)
+
+	if m.BeginRollover() {
+		t.Fatal("expected rollover to be refused while idle")
+	}
+
+	_ = m.Transition(StateIdle, StateStarting)
+	_ = m.Transition(StateStarting, StateRecording)
+	if !m.BeginRollover() {
+		t.Fatal("expected rollover to be claimed while recording")
+	}
+	if m.BeginRollover() {
+		t.Fatal("expected a second rollover to be refused while one is running")
+	}
+
+	// Finalizing waits for the running rollover, and no new one can start
+	_ = m.Transition(StateRecording, StateFinalizing)
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(released)
+		m.EndRollover()
+	}()
+	m.WaitRollover()
+	select {
+	case <-released:
+	default:
+		t.Fatal("WaitRollover returned before the rollover ended")
+	}
+	if m.BeginRollover() {
+		t.Fatal("expected rollover to be refused while finalizing")
+	}
+}
+
+// TestRecordingStateMachineConcurrent hammers the machine the way the hotkey,
+// tray, frontend and progress loop do, checking that each transition is seen
+// once and in order, that at most one rollover runs at a time, and that no
+// rollover is still running once a recording is finalized.
+func TestRecordingStateMachineConcurrent(t *testing.T) {
+	var (
+		eventsMu sync.Mutex
+		events   []StateChangedEvent
+	)
+	m := newRecordingStateMachine(func(event StateChangedEvent) {
+		eventsMu.Lock()
+		events = append(events, event)
+		eventsMu.Unlock()
+	})
+
+	var rollovers, maxRollovers atomic.Int32
+	var finalizedDuringRollover atomic.Bool
+	stop := make(chan struct{})
+	var workers sync.WaitGroup
+
+	run := func(action func()) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					action()
+				}
+			}
+		}()
+	}
+
+	start := func() {
+		if m.Transition(StateIdle, StateStarting) != nil {
+			return
+		}
+		// Starting the recorder fails now and then
+		if time.Now().UnixNano()%5 == 0 {
+			_ = m.Transition(StateStarting, StateIdle)
+			return
+		}
+		if err := m.Transition(StateStarting, StateRecording); err != nil {
+			t.Errorf("starting recording: %v", err)
+		}
+	}
+	finish := func() {
+		if m.Transition(StateRecording, StateFinalizing) != nil {
+			return
+		}
+		m.WaitRollover()
+		if rollovers.Load() != 0 {
+			finalizedDuringRollover.Store(true)
+		}
+		if err := m.Transition(StateFinalizing, StateIdle); err != nil {
+			t.Errorf("finishing recording: %v", err)
+		}
+	}
+	rollover := func() {
+		if !m.BeginRollover() {
+			return
+		}
+		go func() {
+			defer m.EndRollover()
+			n := rollovers.Add(1)
+			for {
+				prev := maxRollovers.Load()
+				if n <= prev || maxRollovers.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Microsecond)
+			rollovers.Add(-1)
+		}()
+	}
+
+	for range 4 {
+		run(start)
+		run(finish) // stop
+		run(finish) // cancel
+		run(rollover)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	close(stop)
+	workers.Wait()
+	m.WaitRollover()
+
+	if n := maxRollovers.Load(); n > 1 {
+		t.Errorf("%d rollovers ran at once, want at most 1", n)
+	}
+	if finalizedDuringRollover.Load() {
+		t.Error("a recording was finalized while its rollover was still running")
+	}
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	if len(events) == 0 {
+		t.Fatal("no transitions were made")
+	}
+	state := StateIdle
+	for i, event := range events {
+		if event.Previous != state {
+			t.Fatalf("event %d moved from %s, but the machine was %s", i, event.Previous, state)
+		}
+		state = event.State
+	}
+	if current := m.Current(); current != state {
+		t.Fatalf("machine is %s, but the last event moved it to %s", current, state)
+	}
+}
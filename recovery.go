@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mac-dictation/internal/audio"
+	"mac-dictation/internal/journal"
+	"slices"
+)
+
+// MinRecoverSecs is the shortest untranscribed tail of a journal worth sending for batch transcription
+const MinRecoverSecs = 0.5
+
+// DetectOrphanedJournals finds journals left behind by recordings that never
+// finished, e.g. because the app crashed. Called at startup, before any recording.
+func (a *App) DetectOrphanedJournals() []journal.Entry {
+	entries, err := journal.List(a.journalDir)
+	if err != nil {
+		slog.Error("failed to list recording journals", "error", err)
+		return nil
+	}
+
+	for _, entry := range entries {
+		slog.Warn("found unfinished recording", "journal", entry.ID, "startedAt", entry.StartedAt, "durationSecs", entry.DurationSecs)
+	}
+	return entries
+}
+
+// GetOrphanedJournals returns recordings that can be recovered, excluding the one in progress
+func (a *App) GetOrphanedJournals() ([]journal.Entry, error) {
+	entries, err := journal.List(a.journalDir)
+	if err != nil {
+		return nil, err
+	}
+
+	a.streamMu.Lock()
+	stream := a.stream
+	a.streamMu.Unlock()
+
+	if stream != nil && stream.journal != nil {
+		entries = slices.DeleteFunc(entries, func(entry journal.Entry) bool {
+			return entry.ID == stream.journal.ID()
+		})
+	}
+	return entries, nil
+}
+
+// RecoverJournal saves an unfinished recording as a message. Final transcript
+// segments journaled during the recording are kept, and any audio after the
+// last segment is batch transcribed with the active provider.
+//
+// The message is added to the thread the recording was made in, or a new
+// thread, without changing the selected thread. Recovery holds the recording
+// state in finalizing so no recording can start meanwhile, and can be
+// aborted with CancelRecording.
+func (a *App) RecoverJournal(id string) (*TranscriptionCompletedEvent, error) {
+	if err := a.state.Transition(StateIdle, StateFinalizing); err != nil {
+		return nil, fmt.Errorf("cannot recover a recording: %w", err)
+	}
+	defer a.transitionState(StateFinalizing, StateIdle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a.streamMu.Lock()
+	a.recordingCtx, a.cancelRecording = ctx, cancel
+	a.streamMu.Unlock()
+
+	entry, err := journal.Load(a.journalDir, id)
+	if err != nil {
+		return nil, err
+	}
+	pcm, err := journal.ReadAudio(a.journalDir, id)
+	if err != nil {
+		return nil, err
+	}
+
+	text := entry.Text()
+	provider := recoveredProviderName(entry.Provider)
+
+	offset := int(entry.TranscribedSecs() * audio.BytesPerSecond)
+	offset = min(offset-offset%audio.BytesPerSample, len(pcm))
+	if remaining := pcm[offset:]; audioDurationSecs(remaining) >= MinRecoverSecs {
+		a.transcriberMu.Lock()
+		transcriber, name := a.transcriber, a.transcriberName
+		a.transcriberMu.Unlock()
+
+		recovered, err := transcriber.Transcribe(ctx, remaining)
+		if err != nil {
+			if text == "" {
+				return nil, fmt.Errorf("failed to transcribe recovered audio: %w", err)
+			}
+			slog.Warn("failed to transcribe end of recovered audio, keeping journaled text", "error", err, "journal", id)
+		} else if recovered.Text != "" {
+			if text == "" {
+				provider = recoveredProviderName(batchProviderName(transcriber, name))
+			} else {
+				text += " "
+			}
+			text += recovered.Text
+		}
+	}
+
+	if text == "" {
+		return nil, fmt.Errorf("no speech found in recovered recording")
+	}
+
+	// Append to the thread the recording was made in if it still exists
+	var threadID *int
+	if entry.ThreadID != nil {
+		if _, err := a.threads.Lookup(*entry.ThreadID); err == nil {
+			threadID = entry.ThreadID
+		}
+	}
+
+	result, err := a.persistTranscription(transcript{
+		text:         text,
+		provider:     provider,
+		durationSecs: audioDurationSecs(pcm),
+		audioData:    pcm,
+		recovered:    true,
+		threadID:     threadID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist recovered recording: %w", err)
+	}
+
+	if err := journal.Discard(a.journalDir, id); err != nil {
+		slog.Error("failed to remove recovered journal", "error", err, "journal", id)
+	}
+	return result, nil
+}
+
+// DiscardJournal deletes an unfinished recording without saving it
+func (a *App) DiscardJournal(id string) error {
+	return journal.Discard(a.journalDir, id)
+}
+
+// recoveredProviderName labels text recovered from a crash journal
+func recoveredProviderName(provider string) string {
+	return provider + "-recovered"
+}
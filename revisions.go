@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"mac-dictation/internal/cleanup"
+	"mac-dictation/internal/diff"
+	"mac-dictation/internal/storage"
+	"strings"
+)
+
+// TextUpdatedEvent is emitted when a message's text is edited or restored
+type TextUpdatedEvent struct {
+	MessageID int    `json:"messageId"`
+	Text      string `json:"text"`
+}
+
+// addRevision saves revision as the new head of message, updating Message.Text to match
+func (a *App) addRevision(message *storage.Message, revision *storage.MessageRevision) error {
+	revision.MessageID = *message.ID
+	if err := a.revisions.PersistHead(revision); err != nil {
+		return fmt.Errorf("failed to persist revision: %w", err)
+	}
+
+	message.Text = revision.Text
+	message.UpdatedAt = revision.CreatedAt
+	return nil
+}
+
+// GetMessageRevisions returns every version of a message's text, oldest first.
+// The last revision is the current text.
+func (a *App) GetMessageRevisions(messageID int) ([]storage.MessageRevision, error) {
+	if _, err := a.messages.Lookup(messageID); err != nil {
+		return nil, err
+	}
+	return a.revisions.LookupForMessage(messageID)
+}
+
+// EditMessageText replaces a message's text with a manual edit, keeping the
+// previous text in its history
+func (a *App) EditMessageText(messageID int, text string) (*storage.Message, error) {
+	message, err := a.messages.Lookup(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("message text is empty")
+	}
+	if text == message.Text {
+		return message, nil
+	}
+
+	if err := a.addRevision(message, &storage.MessageRevision{
+		Text:   text,
+		Source: storage.RevisionManual,
+	}); err != nil {
+		return nil, err
+	}
+
+	a.app.Event.Emit(EventTextUpdated, TextUpdatedEvent{MessageID: *message.ID, Text: message.Text})
+	return message, nil
+}
+
+// RestoreMessageRevision makes an earlier revision the message's current text.
+// The restore is recorded as a new revision so no history is lost.
+func (a *App) RestoreMessageRevision(revisionID int) (*storage.Message, error) {
+	revision, err := a.revisions.Lookup(revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := a.messages.Lookup(revision.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	if err := a.addRevision(message, &storage.MessageRevision{
+		Text:         revision.Text,
+		Source:       storage.RevisionRestore,
+		RestoredFrom: revision.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	a.app.Event.Emit(EventTextUpdated, TextUpdatedEvent{MessageID: *message.ID, Text: message.Text})
+	return message, nil
+}
+
+// DiffMessageRevisions returns a word level diff from one revision to another
+func (a *App) DiffMessageRevisions(fromID, toID int) ([]diff.Op, error) {
+	from, err := a.revisions.Lookup(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := a.revisions.Lookup(toID)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.MessageID != to.MessageID {
+		return nil, fmt.Errorf("revisions %d and %d belong to different messages", fromID, toID)
+	}
+	return diff.Words(from.Text, to.Text), nil
+}
+
+// cleanUpMessage applies the rule-based cleanup to a message's original text,
+// saving the result as a new revision unless it matches the current text
+func (a *App) cleanUpMessage(message *storage.Message) (string, error) {
+	cleaned := cleanup.Clean(message.OriginalText)
+	if cleaned != "" && cleaned != message.Text {
+		if err := a.addRevision(message, &storage.MessageRevision{
+			Text:   cleaned,
+			Source: storage.RevisionCleanup,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	a.app.Event.Emit(EventTextImproved, TextImprovedEvent{
+		MessageID:    *message.ID,
+		ImprovedText: message.Text,
+	})
+	return message.Text, nil
+}
+
+// CleanMessageText tidies a message with the rule-based cleanup, a fast first
+// pass which needs no LLM. The result is saved as a new revision.
+func (a *App) CleanMessageText(messageID int) (*storage.Message, error) {
+	message, err := a.messages.Lookup(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	if _, err := a.cleanUpMessage(message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
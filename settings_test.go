@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"mac-dictation/internal/database"
+	"mac-dictation/internal/storage"
+)
+
+// newSettingsTestApp builds an App with just the services settings need
+func newSettingsTestApp(t *testing.T) *App {
+	t.Helper()
+
+	db, err := database.Connect(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := database.RunMigrations(context.Background(), db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	settings := storage.NewSettingsService(db)
+	vocabulary := storage.NewVocabularyService(db)
+	return &App{
+		settings:   settings,
+		vocabulary: vocabulary,
+		providers:  newProviderRegistry(settings, vocabulary),
+	}
+}
+
+func TestSetSettingTranscriptionProvider(t *testing.T) {
+	a := newSettingsTestApp(t)
+
+	if err := a.SetSetting(SettingTranscriptionProvider, "whisper-cloud"); err == nil {
+		t.Fatal("expected an unknown transcription provider to be rejected")
+	}
+	if value, _ := a.settings.Get(SettingTranscriptionProvider); value != "" {
+		t.Fatalf("rejected provider was saved as %q", value)
+	}
+
+	if err := a.SetSetting(SettingTranscriptionProvider, "local"); err != nil {
+		t.Fatalf("failed to select the local provider: %v", err)
+	}
+	if a.transcriberName != "local" {
+		t.Fatalf("transcriber %q, want local", a.transcriberName)
+	}
+	if !a.AreAPIKeysConfigured() {
+		t.Fatal("expected the local provider to need no API key")
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mac-dictation/internal/prompts"
+	"mac-dictation/internal/storage"
+)
+
+// seedPromptTemplates adds any missing built-in prompt templates
+func (a *App) seedPromptTemplates() {
+	builtins := make([]storage.PromptTemplate, 0, len(prompts.Builtins))
+	for _, builtin := range prompts.Builtins {
+		builtins = append(builtins, storage.PromptTemplate{
+			BuiltinKey: builtin.Key,
+			Name:       builtin.Name,
+			Prompt:     builtin.Prompt,
+		})
+	}
+
+	if err := a.templates.SeedBuiltins(builtins); err != nil {
+		slog.Error("failed to seed prompt templates", "error", err)
+	}
+}
+
+// cleanUpPrompt returns the clean up template's prompt, which the user may have
+// edited, falling back to the built-in prompt
+func (a *App) cleanUpPrompt() string {
+	template, err := a.templates.LookupBuiltin(prompts.BuiltinCleanUp)
+	if err != nil {
+		slog.Warn("failed to load clean up template, using default", "error", err)
+		return prompts.CleanUpPrompt
+	}
+	return template.Prompt
+}
+
+func (a *App) GetPromptTemplates() ([]storage.PromptTemplate, error) {
+	return a.templates.LookupAll()
+}
+
+func (a *App) AddPromptTemplate(name, prompt string) (*storage.PromptTemplate, error) {
+	template := &storage.PromptTemplate{Name: name, Prompt: prompt}
+	if err := a.templates.Persist(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (a *App) UpdatePromptTemplate(id int, name, prompt string) error {
+	template, err := a.templates.Lookup(id)
+	if err != nil {
+		return err
+	}
+
+	template.Name = name
+	template.Prompt = prompt
+	return a.templates.Persist(template)
+}
+
+func (a *App) DeletePromptTemplate(id int) error {
+	return a.templates.Delete(id)
+}
+
+// TransformMessage runs a prompt template against a message's transcript with
+// the configured LLM. The result is saved as a revision alongside the message's
+// history without replacing its current text, so each template run is kept as
+// its own version that can be compared or restored. It returns nil without an
+// error when the transformation is cancelled.
+func (a *App) TransformMessage(messageID, templateID int) (*storage.MessageRevision, error) {
+	message, err := a.messages.Lookup(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	template, err := a.templates.Lookup(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := a.currentLLM()
+	if err != nil {
+		return nil, err
+	}
+	if !model.IsConfigured() {
+		return nil, fmt.Errorf("LLM provider is not configured")
+	}
+
+	terms, err := a.vocabulary.Terms()
+	if err != nil {
+		slog.Error("failed to load vocabulary for transformation", "error", err)
+	}
+	prompt := prompts.WithVocabulary(template.Prompt, terms)
+
+	a.improveMu.Lock()
+	if _, ok := a.transforming[messageID]; ok {
+		a.improveMu.Unlock()
+		return nil, fmt.Errorf("message is already being transformed")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.transforming[messageID] = cancel
+	a.improveMu.Unlock()
+
+	defer func() {
+		a.improveMu.Lock()
+		delete(a.transforming, messageID)
+		a.improveMu.Unlock()
+		cancel()
+	}()
+
+	// Always transform the transcript, so running several templates does not
+	// compound one result into the next
+	text, err := model.Prompt(ctx, prompt, message.OriginalText)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			slog.Info("transformation cancelled", "messageID", messageID)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to transform message: %w", err)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("transformation returned no text")
+	}
+
+	revision := &storage.MessageRevision{
+		MessageID:    messageID,
+		Text:         text,
+		Source:       storage.RevisionTemplate,
+		Prompt:       prompt,
+		Model:        model.Model(),
+		TemplateName: template.Name,
+	}
+	if err := a.revisions.Persist(revision); err != nil {
+		return nil, fmt.Errorf("failed to save transformation: %w", err)
+	}
+	return revision, nil
+}
+
+// CancelTransformMessage aborts an in-flight transformation, leaving the message unchanged
+func (a *App) CancelTransformMessage(messageID int) {
+	a.improveMu.Lock()
+	cancel, ok := a.transforming[messageID]
+	a.improveMu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}